@@ -0,0 +1,28 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+// Scanner is implemented by types that can read themselves
+// from a result column.
+// The [zombiezen.com/go/sqlite/sqlitex] reflection helpers
+// like sqlitex.Query call ScanColumn instead of using their default conversion.
+//
+// ScanColumn should read the column with the Stmt's Column* methods.
+// Column indices start at 0.
+// ScanColumn is called for NULL columns too:
+// implementations can check for NULL with [Stmt.ColumnIsNull].
+type Scanner interface {
+	ScanColumn(stmt *Stmt, col int) error
+}
+
+// Binder is implemented by types that can bind themselves
+// to a statement parameter.
+// The [zombiezen.com/go/sqlite/sqlitex] execution functions
+// call BindParam instead of using their default conversion.
+//
+// BindParam should bind the parameter with the Stmt's Bind* methods.
+// Parameter indices start at 1.
+type Binder interface {
+	BindParam(stmt *Stmt, param int) error
+}
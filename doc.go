@@ -39,7 +39,9 @@ see the documentation on [Stmt.Step] for details.
 The optional SQLite 3 extensions compiled in are:
 session, FTS5, RTree, JSON1, and GeoPoly.
 
-This is not a [database/sql] driver.
+This is not a [database/sql] driver,
+although the opt-in [zombiezen.com/go/sqlite/sqlitedriver] package provides one
+for interoperating with libraries that require it.
 For helper functions to make it easier to execute statements,
 see the [zombiezen.com/go/sqlite/sqlitex] package.
 
@@ -30,3 +30,11 @@ func InterruptedStmt(conn *Conn, query string) *Stmt {
 		prepInterrupt: true,
 	}
 }
+
+// LivePointers returns the number of Go values held by SQLite
+// through the pointer passing interfaces.
+func LivePointers() int {
+	pointers.mu.RLock()
+	defer pointers.mu.RUnlock()
+	return len(pointers.m)
+}
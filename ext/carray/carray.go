@@ -0,0 +1,182 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+// Package carray provides a Go version of the [carray] table-valued function
+// from the SQLite tree.
+// carray exposes a Go slice bound to a statement parameter as a virtual table,
+// so that queries can use it in place of a variable-length list:
+//
+//	SELECT * FROM users WHERE id IN carray(?1);
+//
+// [carray]: https://sqlite.org/carray.html
+package carray
+
+import (
+	"fmt"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// PointerType is the type name for slices passed to carray
+// with [sqlite.Stmt.BindPointer].
+const PointerType = "carray"
+
+// Module is a virtual table module that can be registered with [sqlite.Conn.SetModule].
+var Module = &sqlite.Module{
+	Connect: connect,
+}
+
+// Register registers the "carray" table-valued function on the given connection.
+func Register(c *sqlite.Conn) error {
+	return c.SetModule("carray", Module)
+}
+
+// Bind binds a slice to a numbered stmt parameter for use as the argument to carray.
+// values must be a []int64, []float64, []string, or [][]byte.
+func Bind(stmt *sqlite.Stmt, param int, values any) error {
+	switch values.(type) {
+	case []int64, []float64, []string, [][]byte:
+		stmt.BindPointer(param, PointerType, values)
+		return nil
+	default:
+		return fmt.Errorf("carray: bind %T: unsupported type", values)
+	}
+}
+
+// Slice is a slice that binds itself with [Bind].
+// It implements [sqlite.Binder],
+// so it can be passed as an argument to the
+// [zombiezen.com/go/sqlite/sqlitex] execution functions.
+type Slice[T int64 | float64 | string | []byte] []T
+
+// BindParam binds s as the argument to carray.
+func (s Slice[T]) BindParam(stmt *sqlite.Stmt, param int) error {
+	return Bind(stmt, param, []T(s))
+}
+
+type vtab struct{}
+
+const (
+	carrayColumnValue = iota
+	carrayColumnPointer
+)
+
+func connect(c *sqlite.Conn, opts *sqlite.VTableConnectOptions) (sqlite.VTable, *sqlite.VTableConfig, error) {
+	vtab := new(vtab)
+	cfg := &sqlite.VTableConfig{
+		Declaration:   "CREATE TABLE x(value,pointer hidden)",
+		AllowIndirect: true,
+	}
+	return vtab, cfg, nil
+}
+
+// BestIndex requires an equality constraint against the hidden pointer column,
+// which is the argument to the table-valued function.
+func (vt *vtab) BestIndex(inputs *sqlite.IndexInputs) (*sqlite.IndexOutputs, error) {
+	outputs := &sqlite.IndexOutputs{
+		ConstraintUsage: make([]sqlite.IndexConstraintUsage, len(inputs.Constraints)),
+	}
+	found, unusable := false, false
+	for i, c := range inputs.Constraints {
+		if c.Column != carrayColumnPointer || c.Op != sqlite.IndexConstraintEq {
+			continue
+		}
+		if !c.Usable {
+			unusable = true
+			continue
+		}
+		outputs.ConstraintUsage[i] = sqlite.IndexConstraintUsage{
+			ArgvIndex: 1,
+			Omit:      true,
+		}
+		found = true
+		break
+	}
+	if !found {
+		if unusable {
+			// The argument is an input, so this plan is unusable.
+			return nil, sqlite.ResultConstraint.ToError()
+		}
+		return nil, fmt.Errorf("argument to \"carray()\" missing or unusable")
+	}
+	outputs.ID = sqlite.IndexID{Num: 1}
+	outputs.EstimatedCost = 1
+	outputs.EstimatedRows = 100
+	return outputs, nil
+}
+
+func (vt *vtab) Open() (sqlite.VTableCursor, error) {
+	return new(cursor), nil
+}
+
+func (vt *vtab) Disconnect() error {
+	return nil
+}
+
+func (vt *vtab) Destroy() error {
+	return nil
+}
+
+type cursor struct {
+	values any
+	n      int
+	i      int
+}
+
+// Filter reads the slice passed as the argument to carray
+// and positions the cursor at its first element.
+// Arguments that were not bound with [Bind] produce no rows.
+func (cur *cursor) Filter(id sqlite.IndexID, argv []sqlite.Value) error {
+	cur.values, cur.n, cur.i = nil, 0, 0
+	values := argv[0].Pointer(PointerType)
+	switch values := values.(type) {
+	case nil:
+	case []int64:
+		cur.n = len(values)
+	case []float64:
+		cur.n = len(values)
+	case []string:
+		cur.n = len(values)
+	case [][]byte:
+		cur.n = len(values)
+	default:
+		return fmt.Errorf("carray: unsupported type %T", values)
+	}
+	cur.values = values
+	return nil
+}
+
+func (cur *cursor) Next() error {
+	cur.i++
+	return nil
+}
+
+func (cur *cursor) Column(i int, noChange bool) (sqlite.Value, error) {
+	if i != carrayColumnValue {
+		return sqlite.Value{}, nil
+	}
+	switch values := cur.values.(type) {
+	case []int64:
+		return sqlite.IntegerValue(values[cur.i]), nil
+	case []float64:
+		return sqlite.FloatValue(values[cur.i]), nil
+	case []string:
+		return sqlite.TextValue(values[cur.i]), nil
+	case [][]byte:
+		return sqlite.BlobValue(values[cur.i]), nil
+	default:
+		panic("unreachable")
+	}
+}
+
+func (cur *cursor) RowID() (int64, error) {
+	return int64(cur.i) + 1, nil
+}
+
+func (cur *cursor) EOF() bool {
+	return cur.i >= cur.n
+}
+
+func (cur *cursor) Close() error {
+	return nil
+}
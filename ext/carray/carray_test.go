@@ -0,0 +1,145 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package carray
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+)
+
+func TestCarray(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := Register(c); err != nil {
+		t.Fatal("Register:", err)
+	}
+
+	tests := []struct {
+		name   string
+		values any
+		want   []any
+	}{
+		{
+			name:   "Int64",
+			values: []int64{3, -1, 1 << 40},
+			want:   []any{int64(3), int64(-1), int64(1 << 40)},
+		},
+		{
+			name:   "Float64",
+			values: []float64{1.5, -0.25},
+			want:   []any{1.5, -0.25},
+		},
+		{
+			name:   "String",
+			values: []string{"apple", "", "cherry"},
+			want:   []any{"apple", "", "cherry"},
+		},
+		{
+			name:   "Bytes",
+			values: [][]byte{{0x01, 0x02}, {0xff}},
+			want:   []any{[]byte{0x01, 0x02}, []byte{0xff}},
+		},
+		{
+			name:   "Empty",
+			values: []int64{},
+			want:   nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stmt, _, err := c.PrepareTransient("SELECT value FROM carray(?1);")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stmt.Finalize()
+			if err := Bind(stmt, 1, test.values); err != nil {
+				t.Fatal("Bind:", err)
+			}
+			got, err := columnValues(stmt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("values (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Unbound", func(t *testing.T) {
+		stmt, _, err := c.PrepareTransient("SELECT value FROM carray(?1);")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		stmt.BindText(1, "not a pointer")
+		got, err := columnValues(stmt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) > 0 {
+			t.Errorf("values = %v; want none", got)
+		}
+	})
+
+	t.Run("UnsupportedBind", func(t *testing.T) {
+		stmt, _, err := c.PrepareTransient("SELECT value FROM carray(?1);")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		if err := Bind(stmt, 1, []int{1, 2}); err == nil {
+			t.Error("Bind([]int) did not return an error")
+		}
+	})
+
+	t.Run("UnsupportedPointer", func(t *testing.T) {
+		stmt, _, err := c.PrepareTransient("SELECT value FROM carray(?1);")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		stmt.BindPointer(1, PointerType, []int32{1, 2})
+		if _, err := stmt.Step(); err == nil {
+			t.Error("Step did not return an error for []int32")
+		}
+	})
+}
+
+// columnValues steps stmt to completion
+// and returns the values of its first column.
+func columnValues(stmt *sqlite.Stmt) ([]any, error) {
+	var values []any
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			return values, err
+		}
+		if !hasRow {
+			return values, nil
+		}
+		switch stmt.ColumnType(0) {
+		case sqlite.TypeInteger:
+			values = append(values, stmt.ColumnInt64(0))
+		case sqlite.TypeFloat:
+			values = append(values, stmt.ColumnFloat(0))
+		case sqlite.TypeText:
+			values = append(values, stmt.ColumnText(0))
+		case sqlite.TypeBlob:
+			b := make([]byte, stmt.ColumnLen(0))
+			stmt.ColumnBytes(0, b)
+			values = append(values, b)
+		default:
+			values = append(values, nil)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package carray_test
+
+import (
+	"fmt"
+	"log"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/ext/carray"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func Example() {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer conn.Close()
+
+	if err := carray.Register(conn); err != nil {
+		log.Fatal(err)
+	}
+	err = sqlitex.ExecuteScript(conn, `
+		CREATE TABLE fruits (id INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO fruits (name) VALUES ('apple'), ('banana'), ('cherry'), ('durian');
+	`, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = sqlitex.ExecuteTransient(
+		conn,
+		`SELECT name FROM fruits WHERE id IN carray(?1) ORDER BY id;`,
+		&sqlitex.ExecOptions{
+			Args: []any{carray.Slice[int64]{2, 4}},
+			ResultFunc: func(stmt *sqlite.Stmt) error {
+				fmt.Println(stmt.ColumnText(0))
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// banana
+	// durian
+}
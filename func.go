@@ -125,11 +125,21 @@ func (ctx Context) result(v Value, err error) {
 			return
 		}
 		lib.Xsqlite3_result_value(ctx.tls, ctx.ptr, v.ptrOrType)
+		ctx.resultSubtype(v)
 		return
 	}
 	switch ColumnType(v.ptrOrType) {
 	case 0, TypeNull:
-		lib.Xsqlite3_result_null(ctx.tls, ctx.ptr)
+		if v.p == nil {
+			lib.Xsqlite3_result_null(ctx.tls, ctx.ptr)
+			break
+		}
+		id, ctype, err := newPointer(v.s, v.p)
+		if err != nil {
+			ctx.resultError(fmt.Errorf("function result pointer: %w", err))
+			return
+		}
+		lib.Xsqlite3_result_pointer(ctx.tls, ctx.ptr, id, ctype, cFuncPointer(freePointer))
 	case TypeInteger:
 		lib.Xsqlite3_result_int64(ctx.tls, ctx.ptr, v.n)
 	case TypeFloat:
@@ -160,13 +170,20 @@ func (ctx Context) result(v Value, err error) {
 	default:
 		panic("unknown result Value type")
 	}
+	ctx.resultSubtype(v)
+}
+
+func (ctx Context) resultSubtype(v Value) {
+	if v.hasSubtype {
+		lib.Xsqlite3_result_subtype(ctx.tls, ctx.ptr, uint32(v.subtype))
+	}
 }
 
 func (ctx Context) resultError(err error) {
 	errstr := err.Error()
-	cerrstr, err := libc.CString(errstr)
-	if err != nil {
-		panic(err)
+	cerrstr, allocErr := libc.CString(errstr)
+	if allocErr != nil {
+		panic(allocErr)
 	}
 	defer libc.Xfree(ctx.tls, cerrstr)
 	lib.Xsqlite3_result_error(ctx.tls, ctx.ptr, cerrstr, int32(len(errstr)))
@@ -181,8 +198,13 @@ type Value struct {
 	tls       *libc.TLS
 	ptrOrType uintptr // pointer to sqlite_value if tls != nil, ColumnType otherwise
 
-	s string
-	n int64 // if ptrOrType == 0 and n != 0, then indicates the "nochange" NULL.
+	s string // for PointerValue, the pointer type name
+	n int64  // if ptrOrType == 0 and n != 0, then indicates the "nochange" NULL.
+	p any    // Go value for PointerValue
+
+	// subtype overrides the value's subtype if hasSubtype is true.
+	subtype    uint8
+	hasSubtype bool
 }
 
 // IntegerValue returns a new Value representing the given integer.
@@ -200,6 +222,20 @@ func TextValue(s string) Value {
 	return Value{ptrOrType: uintptr(TypeText), s: s}
 }
 
+// JSONValue returns a new text Value holding the given JSON text
+// with the [JSONSubtype],
+// so SQLite's JSON functions treat it as JSON
+// instead of quoting it as a string.
+// Functions that return a JSONValue should set [FunctionImpl.ResultSubtype].
+// See https://sqlite.org/json1.html#value_arguments for details.
+func JSONValue(s string) Value {
+	return TextValue(s).WithSubtype(JSONSubtype)
+}
+
+// JSONSubtype is the subtype that SQLite's JSON functions
+// use to mark their text results as JSON.
+const JSONSubtype = 'J'
+
 // BlobValue returns a new blob Value, copying the bytes from the given
 // byte slice.
 func BlobValue(b []byte) Value {
@@ -225,6 +261,40 @@ func (v Value) Type() ColumnType {
 	return ColumnType(lib.Xsqlite3_value_type(v.tls, v.ptrOrType))
 }
 
+// Subtype returns the value's [subtype],
+// a small integer that application-defined functions
+// can attach to their results to pass extra information
+// to the functions that consume them.
+// SQLite's JSON functions use [JSONSubtype].
+// Values that have not been given a subtype have a subtype of zero.
+//
+// Functions that call Subtype on their arguments
+// should set [FunctionImpl.SubtypeConsumer]:
+// SQLite may not preserve subtypes for other functions' arguments.
+//
+// [subtype]: https://sqlite.org/c3ref/value_subtype.html
+func (v Value) Subtype() uint8 {
+	if v.hasSubtype {
+		return v.subtype
+	}
+	if v.tls == nil || v.ptrOrType == 0 {
+		return 0
+	}
+	return uint8(lib.Xsqlite3_value_subtype(v.tls, v.ptrOrType))
+}
+
+// WithSubtype returns a copy of v with the given subtype.
+// When returned from an application-defined function,
+// the result has the subtype.
+// Functions that return values with subtypes
+// should set [FunctionImpl.ResultSubtype].
+// See [Value.Subtype] for details.
+func (v Value) WithSubtype(subtype uint8) Value {
+	v.subtype = subtype
+	v.hasSubtype = true
+	return v
+}
+
 // Conversions follow the table in https://sqlite.org/c3ref/column_blob.html
 
 // Int returns the value as an integer.
@@ -417,6 +487,22 @@ type FunctionImpl struct {
 	// https://sqlite.org/c3ref/c_deterministic.html#sqlitedirectonly for more
 	// details. This defaults to false for better security.
 	AllowIndirect bool
+
+	// If SubtypeConsumer is true, then the function may call [Value.Subtype]
+	// on its arguments.
+	//
+	// This is SQLITE_SUBTYPE. See
+	// https://sqlite.org/c3ref/c_deterministic.html#sqlitesubtype for more
+	// details.
+	SubtypeConsumer bool
+
+	// If ResultSubtype is true, then the function may return values with subtypes,
+	// like those created with [Value.WithSubtype] or [JSONValue].
+	//
+	// This is SQLITE_RESULT_SUBTYPE. See
+	// https://sqlite.org/c3ref/c_deterministic.html#sqliteresultsubtype for
+	// more details.
+	ResultSubtype bool
 }
 
 // An AggregateFunction is an invocation of an aggregate function.
@@ -481,6 +567,12 @@ func (c *Conn) CreateFunction(name string, impl *FunctionImpl) error {
 	if !impl.AllowIndirect {
 		eTextRep |= lib.SQLITE_DIRECTONLY
 	}
+	if impl.SubtypeConsumer {
+		eTextRep |= lib.SQLITE_SUBTYPE
+	}
+	if impl.ResultSubtype {
+		eTextRep |= lib.SQLITE_RESULT_SUBTYPE
+	}
 
 	numArgs := impl.NArgs
 	if numArgs < 0 {
@@ -65,6 +65,141 @@ func TestFunc(t *testing.T) {
 	stmt.Finalize()
 }
 
+func TestFuncError(t *testing.T) {
+	c, err := OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	err = c.CreateFunction("fail", &FunctionImpl{
+		NArgs: 1,
+		Scalar: func(ctx Context, args []Value) (Value, error) {
+			if args[0].Int() != 0 {
+				return Value{}, ResultConstraint.ToError()
+			}
+			return Value{}, fmt.Errorf("bork")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query       string
+		wantCode    ResultCode
+		wantMessage string
+	}{
+		{"SELECT fail(0);", ResultError, "bork"},
+		{"SELECT fail(1);", ResultConstraint, ""},
+	}
+	for _, test := range tests {
+		stmt, _, err := c.PrepareTransient(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = stmt.Step()
+		stmt.Finalize()
+		if err == nil {
+			t.Errorf("%s did not return an error", test.query)
+			continue
+		}
+		if got := ErrCode(err); got != test.wantCode {
+			t.Errorf("%s error code = %v; want %v", test.query, got, test.wantCode)
+		}
+		if !strings.Contains(err.Error(), test.wantMessage) {
+			t.Errorf("%s error = %v; want to contain %q", test.query, err, test.wantMessage)
+		}
+	}
+}
+
+func TestFuncSubtype(t *testing.T) {
+	c, err := OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	funcs := map[string]*FunctionImpl{
+		"tag": {
+			NArgs:         2,
+			ResultSubtype: true,
+			Scalar: func(ctx Context, args []Value) (Value, error) {
+				return TextValue(args[0].Text()).WithSubtype(uint8(args[1].Int())), nil
+			},
+		},
+		"retag": {
+			NArgs:         2,
+			ResultSubtype: true,
+			Scalar: func(ctx Context, args []Value) (Value, error) {
+				return args[0].WithSubtype(uint8(args[1].Int())), nil
+			},
+		},
+		"identity": {
+			NArgs:           1,
+			SubtypeConsumer: true,
+			ResultSubtype:   true,
+			Scalar: func(ctx Context, args []Value) (Value, error) {
+				return args[0], nil
+			},
+		},
+		"get_subtype": {
+			NArgs:           1,
+			SubtypeConsumer: true,
+			Scalar: func(ctx Context, args []Value) (Value, error) {
+				return IntegerValue(int64(args[0].Subtype())), nil
+			},
+		},
+		"json_from_go": {
+			NArgs:         0,
+			ResultSubtype: true,
+			Scalar: func(ctx Context, args []Value) (Value, error) {
+				return JSONValue(`{"a":1}`), nil
+			},
+		},
+	}
+	for name, impl := range funcs {
+		if err := c.CreateFunction(name, impl); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT get_subtype('x');", "0"},
+		{"SELECT get_subtype(tag('x', 7));", "7"},
+		{"SELECT get_subtype(json('[]'));", "74"},
+		{"SELECT get_subtype(identity(json('[]')));", "74"},
+		{"SELECT get_subtype(retag(json('[]'), 0));", "0"},
+		{"SELECT get_subtype(json_from_go());", "74"},
+		{"SELECT json_array(json_from_go());", `[{"a":1}]`},
+		{"SELECT json_array(retag(json_from_go(), 0));", `["{\"a\":1}"]`},
+	}
+	for _, test := range tests {
+		stmt, _, err := c.PrepareTransient(test.query)
+		if err != nil {
+			t.Errorf("%s: %v", test.query, err)
+			continue
+		}
+		if _, err := stmt.Step(); err != nil {
+			t.Errorf("%s: %v", test.query, err)
+		} else if got := stmt.ColumnText(0); got != test.want {
+			t.Errorf("%s = %s; want %s", test.query, got, test.want)
+		}
+		stmt.Finalize()
+	}
+}
+
 func TestAggFunc(t *testing.T) {
 	c, err := OpenConn(":memory:", 0)
 	if err != nil {
@@ -0,0 +1,304 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"modernc.org/libc"
+	lib "modernc.org/sqlite/lib"
+)
+
+// SetUpdateHook registers a function that is called
+// whenever a row is inserted, updated, or deleted in a rowid table.
+// op is one of [OpInsert], [OpUpdate], or [OpDelete],
+// db is the name of the database containing the affected row (e.g. "main"),
+// table is the name of the table containing the affected row,
+// and rowid is the rowid of the affected row.
+// In the case of an update, rowid is the rowid after the update takes place.
+//
+// The update hook is not invoked when internal system tables are modified,
+// for WITHOUT ROWID tables, or for rows deleted by the truncate optimization.
+// The hook function must not modify the database connection,
+// including by preparing or running statements.
+//
+// SetUpdateHook(nil) clears any update hook previously set.
+//
+// https://sqlite.org/c3ref/update_hook.html
+func (c *Conn) SetUpdateHook(fn func(op OpType, db, table string, rowid int64)) {
+	if c == nil {
+		return
+	}
+	if fn == nil {
+		lib.Xsqlite3_update_hook(c.tls, c.conn, 0, 0)
+		hooks.mu.Lock()
+		delete(hooks.update, c.conn)
+		hooks.mu.Unlock()
+		return
+	}
+	hooks.mu.Lock()
+	if hooks.update == nil {
+		hooks.update = make(map[uintptr]func(OpType, string, string, int64))
+	}
+	hooks.update[c.conn] = fn
+	hooks.mu.Unlock()
+	lib.Xsqlite3_update_hook(c.tls, c.conn, cFuncPointer(updateHookTrampoline), c.conn)
+}
+
+func updateHookTrampoline(tls *libc.TLS, conn uintptr, op int32, cDB, cTable uintptr, rowid int64) {
+	hooks.mu.RLock()
+	fn := hooks.update[conn]
+	hooks.mu.RUnlock()
+	if fn == nil {
+		return
+	}
+	fn(OpType(op), libc.GoString(cDB), libc.GoString(cTable), rowid)
+}
+
+// SetCommitHook registers a function that is called
+// whenever a transaction is about to be committed.
+// If fn returns false, then the commit is converted into a rollback.
+// The hook function must not modify the database connection,
+// including by preparing or running statements.
+//
+// SetCommitHook(nil) clears any commit hook previously set.
+//
+// https://sqlite.org/c3ref/commit_hook.html
+func (c *Conn) SetCommitHook(fn func() bool) {
+	if c == nil {
+		return
+	}
+	if fn == nil {
+		lib.Xsqlite3_commit_hook(c.tls, c.conn, 0, 0)
+		hooks.mu.Lock()
+		delete(hooks.commit, c.conn)
+		hooks.mu.Unlock()
+		return
+	}
+	hooks.mu.Lock()
+	if hooks.commit == nil {
+		hooks.commit = make(map[uintptr]func() bool)
+	}
+	hooks.commit[c.conn] = fn
+	hooks.mu.Unlock()
+	lib.Xsqlite3_commit_hook(c.tls, c.conn, cFuncPointer(commitHookTrampoline), c.conn)
+}
+
+func commitHookTrampoline(tls *libc.TLS, conn uintptr) int32 {
+	hooks.mu.RLock()
+	fn := hooks.commit[conn]
+	hooks.mu.RUnlock()
+	if fn == nil || fn() {
+		return 0
+	}
+	// A non-zero return converts the COMMIT into a ROLLBACK.
+	return 1
+}
+
+// SetRollbackHook registers a function that is called
+// whenever a transaction is rolled back.
+// The rollback hook is not invoked
+// if the rollback is the result of the connection closing.
+// The hook function must not modify the database connection,
+// including by preparing or running statements.
+//
+// SetRollbackHook(nil) clears any rollback hook previously set.
+//
+// https://sqlite.org/c3ref/commit_hook.html
+func (c *Conn) SetRollbackHook(fn func()) {
+	if c == nil {
+		return
+	}
+	if fn == nil {
+		lib.Xsqlite3_rollback_hook(c.tls, c.conn, 0, 0)
+		hooks.mu.Lock()
+		delete(hooks.rollback, c.conn)
+		hooks.mu.Unlock()
+		return
+	}
+	hooks.mu.Lock()
+	if hooks.rollback == nil {
+		hooks.rollback = make(map[uintptr]func())
+	}
+	hooks.rollback[c.conn] = fn
+	hooks.mu.Unlock()
+	lib.Xsqlite3_rollback_hook(c.tls, c.conn, cFuncPointer(rollbackHookTrampoline), c.conn)
+}
+
+func rollbackHookTrampoline(tls *libc.TLS, conn uintptr) {
+	hooks.mu.RLock()
+	fn := hooks.rollback[conn]
+	hooks.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// PreUpdate holds information about a change
+// that is about to be made to a row in a database table.
+// It is passed to the function registered with [Conn.SetPreUpdateHook]
+// and is not valid past the return of that function.
+type PreUpdate struct {
+	tls  *libc.TLS
+	conn uintptr
+
+	// Type is one of OpInsert, OpDelete, or OpUpdate.
+	Type OpType
+	// DatabaseName is the name of the database containing the row
+	// (e.g. "main", "temp", or the name of an attached database).
+	DatabaseName string
+	// TableName is the name of the table containing the row.
+	TableName string
+	// OldRowID is the rowid of the row before the change.
+	// It is undefined for OpInsert and for WITHOUT ROWID tables.
+	OldRowID int64
+	// NewRowID is the rowid of the row after the change.
+	// It is undefined for OpDelete and for WITHOUT ROWID tables.
+	NewRowID int64
+}
+
+// Old returns the value of a column in the row before the change.
+// Column indices start at 0.
+// Old returns an error for OpInsert changes.
+// The returned value is valid until the pre-update hook function returns.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (pu *PreUpdate) Old(col int) (Value, error) {
+	ppValue, err := malloc(pu.tls, ptrSize)
+	if err != nil {
+		return Value{}, fmt.Errorf("sqlite: get pre-update old value: %v", err)
+	}
+	defer libc.Xfree(pu.tls, ppValue)
+	res := ResultCode(lib.Xsqlite3_preupdate_old(pu.tls, pu.conn, int32(col), ppValue))
+	if err := res.ToError(); err != nil {
+		return Value{}, fmt.Errorf("sqlite: get pre-update old value: %w", err)
+	}
+	return Value{
+		tls:       pu.tls,
+		ptrOrType: *(*uintptr)(unsafe.Pointer(ppValue)),
+	}, nil
+}
+
+// New returns the value of a column in the row after the change.
+// Column indices start at 0.
+// New returns an error for OpDelete changes.
+// The returned value is valid until the pre-update hook function returns.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (pu *PreUpdate) New(col int) (Value, error) {
+	ppValue, err := malloc(pu.tls, ptrSize)
+	if err != nil {
+		return Value{}, fmt.Errorf("sqlite: get pre-update new value: %v", err)
+	}
+	defer libc.Xfree(pu.tls, ppValue)
+	res := ResultCode(lib.Xsqlite3_preupdate_new(pu.tls, pu.conn, int32(col), ppValue))
+	if err := res.ToError(); err != nil {
+		return Value{}, fmt.Errorf("sqlite: get pre-update new value: %w", err)
+	}
+	return Value{
+		tls:       pu.tls,
+		ptrOrType: *(*uintptr)(unsafe.Pointer(ppValue)),
+	}, nil
+}
+
+// Count returns the number of columns in the row being changed.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (pu *PreUpdate) Count() int {
+	return int(lib.Xsqlite3_preupdate_count(pu.tls, pu.conn))
+}
+
+// Depth returns the trigger depth of the change:
+// 0 if the change was caused directly by a top-level SQL statement,
+// 1 if it was caused by a trigger fired by a top-level statement,
+// and so on.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (pu *PreUpdate) Depth() int {
+	return int(lib.Xsqlite3_preupdate_depth(pu.tls, pu.conn))
+}
+
+// BlobWrite returns the index of the column being written
+// if the change is the result of a write through a [Blob]
+// or -1 otherwise.
+// Blob writes are reported as OpDelete changes
+// without any new values.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (pu *PreUpdate) BlobWrite() int {
+	return int(lib.Xsqlite3_preupdate_blobwrite(pu.tls, pu.conn))
+}
+
+// SetPreUpdateHook registers a function that is called
+// before each change to a row in a database table,
+// including changes to WITHOUT ROWID tables.
+// Unlike the hook registered with [Conn.SetUpdateHook],
+// the pre-update hook has access to the values
+// of the row both before and after the change.
+// The hook function must not modify the database connection,
+// including by preparing or running statements.
+//
+// SetPreUpdateHook(nil) clears any pre-update hook previously set.
+//
+// https://sqlite.org/c3ref/preupdate_blobwrite.html
+func (c *Conn) SetPreUpdateHook(fn func(*PreUpdate)) {
+	if c == nil {
+		return
+	}
+	if fn == nil {
+		lib.Xsqlite3_preupdate_hook(c.tls, c.conn, 0, 0)
+		hooks.mu.Lock()
+		delete(hooks.preupdate, c.conn)
+		hooks.mu.Unlock()
+		return
+	}
+	hooks.mu.Lock()
+	if hooks.preupdate == nil {
+		hooks.preupdate = make(map[uintptr]func(*PreUpdate))
+	}
+	hooks.preupdate[c.conn] = fn
+	hooks.mu.Unlock()
+	lib.Xsqlite3_preupdate_hook(c.tls, c.conn, cFuncPointer(preUpdateHookTrampoline), c.conn)
+}
+
+func preUpdateHookTrampoline(tls *libc.TLS, conn uintptr, db uintptr, op int32, cDB, cTable uintptr, oldRowID, newRowID int64) {
+	hooks.mu.RLock()
+	fn := hooks.preupdate[conn]
+	hooks.mu.RUnlock()
+	if fn == nil {
+		return
+	}
+	fn(&PreUpdate{
+		tls:          tls,
+		conn:         db,
+		Type:         OpType(op),
+		DatabaseName: libc.GoString(cDB),
+		TableName:    libc.GoString(cTable),
+		OldRowID:     oldRowID,
+		NewRowID:     newRowID,
+	})
+}
+
+func (c *Conn) releaseHooks() {
+	hooks.mu.Lock()
+	delete(hooks.update, c.conn)
+	delete(hooks.commit, c.conn)
+	delete(hooks.rollback, c.conn)
+	delete(hooks.preupdate, c.conn)
+	delete(hooks.trace, c.conn)
+	delete(hooks.wal, c.conn)
+	hooks.mu.Unlock()
+}
+
+var hooks struct {
+	mu        sync.RWMutex
+	update    map[uintptr]func(OpType, string, string, int64) // sqlite3* -> update hook
+	commit    map[uintptr]func() bool                         // sqlite3* -> commit hook
+	rollback  map[uintptr]func()                              // sqlite3* -> rollback hook
+	preupdate map[uintptr]func(*PreUpdate)                    // sqlite3* -> pre-update hook
+	trace     map[uintptr]func(TraceEvent)                    // sqlite3* -> trace function
+	wal       map[uintptr]func(string, int) error             // sqlite3* -> WAL hook
+}
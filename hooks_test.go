@@ -0,0 +1,217 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestSetUpdateHook(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(c, "CREATE TABLE foo (id INTEGER PRIMARY KEY, x TEXT);", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	type update struct {
+		Op    sqlite.OpType
+		DB    string
+		Table string
+		RowID int64
+	}
+	var got []update
+	c.SetUpdateHook(func(op sqlite.OpType, db, table string, rowid int64) {
+		got = append(got, update{op, db, table, rowid})
+	})
+	err = sqlitex.ExecuteScript(c, `
+		INSERT INTO foo (id, x) VALUES (1, 'a'), (2, 'b');
+		UPDATE foo SET x = 'c' WHERE id = 2;
+		DELETE FROM foo WHERE id = 1;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []update{
+		{sqlite.OpInsert, "main", "foo", 1},
+		{sqlite.OpInsert, "main", "foo", 2},
+		{sqlite.OpUpdate, "main", "foo", 2},
+		{sqlite.OpDelete, "main", "foo", 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("updates (-want +got):\n%s", diff)
+	}
+
+	got = nil
+	c.SetUpdateHook(nil)
+	if err := sqlitex.ExecuteTransient(c, "INSERT INTO foo (id, x) VALUES (3, 'd');", nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) > 0 {
+		t.Errorf("update hook called %d times after being cleared", len(got))
+	}
+}
+
+func TestSetCommitHook(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(c, "CREATE TABLE foo (x INTEGER);", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, rollbacks := 0, 0
+	allowCommit := true
+	c.SetCommitHook(func() bool {
+		commits++
+		return allowCommit
+	})
+	c.SetRollbackHook(func() {
+		rollbacks++
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		commits, rollbacks = 0, 0
+		allowCommit = true
+		if err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (1);", nil); err != nil {
+			t.Fatal(err)
+		}
+		if commits != 1 || rollbacks != 0 {
+			t.Errorf("commits, rollbacks = %d, %d; want 1, 0", commits, rollbacks)
+		}
+	})
+
+	t.Run("Veto", func(t *testing.T) {
+		commits, rollbacks = 0, 0
+		allowCommit = false
+		err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (2);", nil)
+		if got, want := sqlite.ErrCode(err), sqlite.ResultConstraintCommitHook; got != want {
+			t.Errorf("INSERT error code = %v; want %v", got, want)
+		}
+		if commits != 1 || rollbacks != 1 {
+			t.Errorf("commits, rollbacks = %d, %d; want 1, 1", commits, rollbacks)
+		}
+		n, err := sqlitex.ResultInt(c.Prep("SELECT count(*) FROM foo;"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("count(*) = %d; want 1", n)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		commits, rollbacks = 0, 0
+		allowCommit = true
+		for _, query := range []string{"BEGIN;", "INSERT INTO foo VALUES (3);", "ROLLBACK;"} {
+			if err := sqlitex.ExecuteTransient(c, query, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if commits != 0 || rollbacks != 1 {
+			t.Errorf("commits, rollbacks = %d, %d; want 0, 1", commits, rollbacks)
+		}
+	})
+
+	t.Run("Cleared", func(t *testing.T) {
+		c.SetCommitHook(nil)
+		c.SetRollbackHook(nil)
+		commits, rollbacks = 0, 0
+		allowCommit = false
+		if err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (4);", nil); err != nil {
+			t.Fatal(err)
+		}
+		if commits != 0 || rollbacks != 0 {
+			t.Errorf("commits, rollbacks = %d, %d; want 0, 0", commits, rollbacks)
+		}
+	})
+}
+
+func TestSetPreUpdateHook(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(c, "CREATE TABLE foo (id INTEGER PRIMARY KEY, x TEXT);", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	type change struct {
+		Op       sqlite.OpType
+		Table    string
+		OldRowID int64
+		NewRowID int64
+		Count    int
+		Depth    int
+		Old      string
+		New      string
+	}
+	var got []change
+	c.SetPreUpdateHook(func(pu *sqlite.PreUpdate) {
+		ch := change{
+			Op:    pu.Type,
+			Table: pu.TableName,
+			Count: pu.Count(),
+			Depth: pu.Depth(),
+		}
+		if pu.Type != sqlite.OpInsert {
+			ch.OldRowID = pu.OldRowID
+			v, err := pu.Old(1)
+			if err != nil {
+				t.Error(err)
+			}
+			ch.Old = v.Text()
+		} else if _, err := pu.Old(1); err == nil {
+			t.Error("Old did not return an error for insert")
+		}
+		if pu.Type != sqlite.OpDelete {
+			ch.NewRowID = pu.NewRowID
+			v, err := pu.New(1)
+			if err != nil {
+				t.Error(err)
+			}
+			ch.New = v.Text()
+		} else if _, err := pu.New(1); err == nil {
+			t.Error("New did not return an error for delete")
+		}
+		got = append(got, ch)
+	})
+	err = sqlitex.ExecuteScript(c, `
+		INSERT INTO foo (id, x) VALUES (1, 'a');
+		UPDATE foo SET x = 'b' WHERE id = 1;
+		DELETE FROM foo WHERE id = 1;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []change{
+		{Op: sqlite.OpInsert, Table: "foo", NewRowID: 1, Count: 2, New: "a"},
+		{Op: sqlite.OpUpdate, Table: "foo", OldRowID: 1, NewRowID: 1, Count: 2, Old: "a", New: "b"},
+		{Op: sqlite.OpDelete, Table: "foo", OldRowID: 1, Count: 2, Old: "b"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("changes (-want +got):\n%s", diff)
+	}
+}
@@ -62,6 +62,25 @@ const (
 	OpenFullMutex OpenFlags = lib.SQLITE_OPEN_FULLMUTEX
 )
 
+// Flags that are only passed to [VFS] Open.
+// Exactly one of the file type flags (OpenMainDB through OpenSuperJournal, or OpenWAL)
+// is set to indicate the kind of file being opened.
+const (
+	// OpenDeleteOnClose indicates that the file should be deleted when it is closed.
+	OpenDeleteOnClose OpenFlags = lib.SQLITE_OPEN_DELETEONCLOSE
+	// OpenExclusive is always used with OpenCreate
+	// and indicates that the open should fail if the file already exists.
+	OpenExclusive OpenFlags = lib.SQLITE_OPEN_EXCLUSIVE
+
+	OpenMainDB       OpenFlags = lib.SQLITE_OPEN_MAIN_DB
+	OpenTempDB       OpenFlags = lib.SQLITE_OPEN_TEMP_DB
+	OpenTransientDB  OpenFlags = lib.SQLITE_OPEN_TRANSIENT_DB
+	OpenMainJournal  OpenFlags = lib.SQLITE_OPEN_MAIN_JOURNAL
+	OpenTempJournal  OpenFlags = lib.SQLITE_OPEN_TEMP_JOURNAL
+	OpenSubjournal   OpenFlags = lib.SQLITE_OPEN_SUBJOURNAL
+	OpenSuperJournal OpenFlags = lib.SQLITE_OPEN_SUPER_JOURNAL
+)
+
 // String returns a pipe-separated list of the C constant names set in flags.
 func (flags OpenFlags) String() string {
 	var parts []string
@@ -105,6 +124,42 @@ func (flags OpenFlags) String() string {
 		parts = append(parts, "SQLITE_OPEN_WAL")
 		flags &^= OpenWAL
 	}
+	if flags&OpenDeleteOnClose != 0 {
+		parts = append(parts, "SQLITE_OPEN_DELETEONCLOSE")
+		flags &^= OpenDeleteOnClose
+	}
+	if flags&OpenExclusive != 0 {
+		parts = append(parts, "SQLITE_OPEN_EXCLUSIVE")
+		flags &^= OpenExclusive
+	}
+	if flags&OpenMainDB != 0 {
+		parts = append(parts, "SQLITE_OPEN_MAIN_DB")
+		flags &^= OpenMainDB
+	}
+	if flags&OpenTempDB != 0 {
+		parts = append(parts, "SQLITE_OPEN_TEMP_DB")
+		flags &^= OpenTempDB
+	}
+	if flags&OpenTransientDB != 0 {
+		parts = append(parts, "SQLITE_OPEN_TRANSIENT_DB")
+		flags &^= OpenTransientDB
+	}
+	if flags&OpenMainJournal != 0 {
+		parts = append(parts, "SQLITE_OPEN_MAIN_JOURNAL")
+		flags &^= OpenMainJournal
+	}
+	if flags&OpenTempJournal != 0 {
+		parts = append(parts, "SQLITE_OPEN_TEMP_JOURNAL")
+		flags &^= OpenTempJournal
+	}
+	if flags&OpenSubjournal != 0 {
+		parts = append(parts, "SQLITE_OPEN_SUBJOURNAL")
+		flags &^= OpenSubjournal
+	}
+	if flags&OpenSuperJournal != 0 {
+		parts = append(parts, "SQLITE_OPEN_SUPER_JOURNAL")
+		flags &^= OpenSuperJournal
+	}
 	if flags != 0 || len(parts) == 0 {
 		parts = append(parts, fmt.Sprintf("%#x", uint(flags)))
 	}
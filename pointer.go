@@ -0,0 +1,151 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"fmt"
+	"sync"
+
+	"modernc.org/libc"
+	lib "modernc.org/sqlite/lib"
+)
+
+// pointers holds the Go values passed to SQLite
+// with the [pointer passing interfaces].
+// SQLite is given the value's ID and calls freePointer
+// when it no longer holds the pointer.
+//
+// [pointer passing interfaces]: https://sqlite.org/bindptr.html
+var pointers struct {
+	mu  sync.RWMutex
+	m   map[uintptr]any
+	ids idGen
+
+	// typeNames maps pointer type names to C strings.
+	// SQLite requires that the type strings live as long as the pointers,
+	// so the strings are never freed.
+	typeNames map[string]uintptr
+}
+
+// newPointer stores v in the pointer table
+// and returns its ID and the C string for typeName.
+func newPointer(typeName string, v any) (id uintptr, ctype uintptr, err error) {
+	pointers.mu.Lock()
+	defer pointers.mu.Unlock()
+	ctype, err = pointerTypeLocked(typeName)
+	if err != nil {
+		return 0, 0, err
+	}
+	id = pointers.ids.next()
+	if pointers.m == nil {
+		pointers.m = make(map[uintptr]any)
+	}
+	pointers.m[id] = v
+	return id, ctype, nil
+}
+
+func pointerTypeLocked(typeName string) (uintptr, error) {
+	if ctype := pointers.typeNames[typeName]; ctype != 0 {
+		return ctype, nil
+	}
+	ctype, err := libc.CString(typeName)
+	if err != nil {
+		return 0, err
+	}
+	if pointers.typeNames == nil {
+		pointers.typeNames = make(map[string]uintptr)
+	}
+	pointers.typeNames[typeName] = ctype
+	return ctype, nil
+}
+
+func freePointer(tls *libc.TLS, id uintptr) {
+	pointers.mu.Lock()
+	defer pointers.mu.Unlock()
+	delete(pointers.m, id)
+	pointers.ids.reclaim(id)
+}
+
+// BindPointer binds an arbitrary Go value to a numbered stmt parameter
+// using SQLite's [pointer passing interfaces].
+// The parameter appears as NULL to SQL,
+// but Go code that receives it as a [Value],
+// like an application-defined function or a virtual table's [VTableCursor.Filter],
+// can retrieve v by calling [Value.Pointer] with the same typeName.
+// If v is nil, BindPointer binds NULL.
+//
+// Parameter indices start at 1.
+//
+// [pointer passing interfaces]: https://sqlite.org/bindptr.html
+func (stmt *Stmt) BindPointer(param int, typeName string, v any) {
+	if stmt.stmt == 0 {
+		return
+	}
+	if v == nil {
+		stmt.BindNull(param)
+		return
+	}
+	id, ctype, err := newPointer(typeName, v)
+	if err != nil {
+		if stmt.bindErr == nil {
+			stmt.bindErr = fmt.Errorf("bind pointer: %w", err)
+		}
+		return
+	}
+	res := ResultCode(lib.Xsqlite3_bind_pointer(stmt.conn.tls, stmt.stmt, int32(param), id, ctype, cFuncPointer(freePointer)))
+	stmt.handleBindErr("bind pointer", res)
+}
+
+// SetPointer binds an arbitrary Go value to a parameter using a column name
+// like [Stmt.BindPointer].
+// An invalid parameter name will cause the call to Step to return an error.
+func (stmt *Stmt) SetPointer(param string, typeName string, v any) {
+	stmt.BindPointer(stmt.findBindName("SetPointer", param), typeName, v)
+}
+
+// PointerValue returns a new Value that passes v
+// using SQLite's [pointer passing interfaces].
+// Like a parameter bound with [Stmt.BindPointer],
+// the value appears as NULL to SQL,
+// but Go code that receives it can retrieve v
+// by calling [Value.Pointer] with the same typeName.
+// PointerValue can be returned from application-defined functions
+// and a virtual table's [VTableCursor.Column] method.
+//
+// [pointer passing interfaces]: https://sqlite.org/bindptr.html
+func PointerValue(typeName string, v any) Value {
+	if v == nil {
+		return Value{}
+	}
+	return Value{ptrOrType: uintptr(TypeNull), s: typeName, p: v}
+}
+
+// Pointer returns the Go value passed with [Stmt.BindPointer]
+// or [PointerValue] with the same typeName.
+// If the value was not passed as a pointer
+// or was passed with a different type name,
+// then Pointer returns nil.
+func (v Value) Pointer(typeName string) any {
+	if v.tls == nil {
+		if v.p == nil || v.s != typeName {
+			return nil
+		}
+		return v.p
+	}
+	if v.ptrOrType == 0 {
+		return nil
+	}
+	pointers.mu.RLock()
+	defer pointers.mu.RUnlock()
+	ctype := pointers.typeNames[typeName]
+	if ctype == 0 {
+		// Nothing has been passed with this type name.
+		return nil
+	}
+	id := lib.Xsqlite3_value_pointer(v.tls, v.ptrOrType, ctype)
+	if id == 0 {
+		return nil
+	}
+	return pointers.m[id]
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+)
+
+func TestBindPointer(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	type payload struct{ s string }
+	err = conn.CreateFunction("payload_text", &sqlite.FunctionImpl{
+		NArgs: 1,
+		Scalar: func(ctx sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+			p, ok := args[0].Pointer("payload").(*payload)
+			if !ok {
+				return sqlite.Value{}, nil
+			}
+			return sqlite.TextValue(p.s), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err := conn.PrepareTransient(`SELECT payload_text(?1), ?1 IS NULL, payload_text(?2), payload_text('x');`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stmt.Finalize()
+	stmt.BindPointer(1, "payload", &payload{"hello"})
+	stmt.BindPointer(2, "other", &payload{"wrong type"})
+	if _, err := stmt.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stmt.ColumnText(0), "hello"; got != want {
+		t.Errorf("payload_text(?1) = %q; want %q", got, want)
+	}
+	if !stmt.ColumnBool(1) {
+		t.Error("?1 IS NULL = false; want true")
+	}
+	if !stmt.ColumnIsNull(2) {
+		t.Errorf("payload_text(?2) = %q; want NULL", stmt.ColumnText(2))
+	}
+	if !stmt.ColumnIsNull(3) {
+		t.Errorf("payload_text('x') = %q; want NULL", stmt.ColumnText(3))
+	}
+}
+
+func TestPointerValue(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	type payload struct{ s string }
+	err = conn.CreateFunction("make_payload", &sqlite.FunctionImpl{
+		NArgs: 1,
+		Scalar: func(ctx sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+			return sqlite.PointerValue("payload", &payload{args[0].Text()}), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = conn.CreateFunction("payload_text", &sqlite.FunctionImpl{
+		NArgs: 1,
+		Scalar: func(ctx sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+			p, ok := args[0].Pointer("payload").(*payload)
+			if !ok {
+				return sqlite.Value{}, nil
+			}
+			return sqlite.TextValue(p.s), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	before := sqlite.LivePointers()
+	stmt, _, err := conn.PrepareTransient(`SELECT payload_text(make_payload(:s)), make_payload(:s) IS NULL;`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.SetText(":s", "hello")
+	if _, err := stmt.Step(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stmt.ColumnText(0), "hello"; got != want {
+		t.Errorf("payload_text(make_payload(:s)) = %q; want %q", got, want)
+	}
+	if !stmt.ColumnBool(1) {
+		t.Error("make_payload(:s) IS NULL = false; want true")
+	}
+	if err := stmt.Finalize(); err != nil {
+		t.Error(err)
+	}
+	if got := sqlite.LivePointers(); got != before {
+		t.Errorf("%d pointers live after Finalize; want %d", got, before)
+	}
+
+	v := sqlite.PointerValue("payload", &payload{"go"})
+	if got, ok := v.Pointer("payload").(*payload); !ok || got.s != "go" {
+		t.Errorf("PointerValue(...).Pointer(\"payload\") = %#v; want &payload{s: \"go\"}", v.Pointer("payload"))
+	}
+	if got := v.Pointer("other"); got != nil {
+		t.Errorf("PointerValue(...).Pointer(\"other\") = %#v; want <nil>", got)
+	}
+	if got := v.Type(); got != sqlite.TypeNull {
+		t.Errorf("PointerValue(...).Type() = %v; want %v", got, sqlite.TypeNull)
+	}
+}
@@ -20,6 +20,7 @@ package sqlite
 import (
 	"fmt"
 	"io"
+	"iter"
 	"runtime"
 	"sync"
 	"unsafe"
@@ -350,36 +351,36 @@ func NewChangesetIterator(r io.Reader) (*ChangesetIterator, error) {
 		tls.Close()
 		return nil, fmt.Errorf("sqlite: start changeset iterator: %w", err)
 	}
-	iter := &ChangesetIterator{
+	ci := &ChangesetIterator{
 		tls:    tls,
 		ownTLS: true,
 		ptr:    *(*uintptr)(unsafe.Pointer(pp)),
 		pIn:    pIn,
 	}
-	runtime.SetFinalizer(iter, func(iter *ChangesetIterator) {
-		if iter.ptr != 0 {
+	runtime.SetFinalizer(ci, func(ci *ChangesetIterator) {
+		if ci.ptr != 0 {
 			panic("open *sqlite.ChangesetIterator garbage collected, call Finalize method")
 		}
 	})
-	return iter, nil
+	return ci, nil
 }
 
 // Close releases any resources associated with the iterator created with
 // NewChangesetIterator.
-func (iter *ChangesetIterator) Close() error {
-	if iter.ptr == 0 {
+func (ci *ChangesetIterator) Close() error {
+	if ci.ptr == 0 {
 		return fmt.Errorf("sqlite: finalize changeset iterator: called twice on same iterator")
 	}
-	res := ResultCode(lib.Xsqlite3changeset_finalize(iter.tls, iter.ptr))
-	iter.ptr = 0
-	if iter.ownTLS {
-		iter.tls.Close()
+	res := ResultCode(lib.Xsqlite3changeset_finalize(ci.tls, ci.ptr))
+	ci.ptr = 0
+	if ci.ownTLS {
+		ci.tls.Close()
 	}
-	iter.tls = nil
-	if iter.pIn != 0 {
-		unregisterStreamReader(iter.pIn)
+	ci.tls = nil
+	if ci.pIn != 0 {
+		unregisterStreamReader(ci.pIn)
 	}
-	iter.pIn = 0
+	ci.pIn = 0
 	if err := res.ToError(); err != nil {
 		return fmt.Errorf("sqlite: finalize changeset iterator: %w", err)
 	}
@@ -391,8 +392,8 @@ func (iter *ChangesetIterator) Close() error {
 // conflict handler.
 //
 // https://www.sqlite.org/session/sqlite3changeset_next.html
-func (iter *ChangesetIterator) Next() (rowReturned bool, err error) {
-	res := ResultCode(lib.Xsqlite3changeset_next(iter.tls, iter.ptr))
+func (ci *ChangesetIterator) Next() (rowReturned bool, err error) {
+	res := ResultCode(lib.Xsqlite3changeset_next(ci.tls, ci.ptr))
 	switch res {
 	case ResultRow:
 		return true, nil
@@ -403,6 +404,37 @@ func (iter *ChangesetIterator) Next() (rowReturned bool, err error) {
 	}
 }
 
+// All returns an iterator over the remaining changes in the changeset.
+// Each change is yielded with its operation,
+// and the change's values can be read with the iterator's
+// [ChangesetIterator.Old] and [ChangesetIterator.New] methods
+// until the next iteration.
+// If an error occurs, it is yielded with a nil operation
+// and iteration stops.
+// All does not close the iterator.
+func (ci *ChangesetIterator) All() iter.Seq2[*ChangesetOperation, error] {
+	return func(yield func(*ChangesetOperation, error) bool) {
+		for {
+			rowReturned, err := ci.Next()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !rowReturned {
+				return
+			}
+			op, err := ci.Operation()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(op, nil) {
+				return
+			}
+		}
+	}
+}
+
 // ChangesetOperation holds information about a change in a changeset.
 type ChangesetOperation struct {
 	// Type is one of OpInsert, OpDelete, or OpUpdate.
@@ -420,31 +452,31 @@ type ChangesetOperation struct {
 // Operation obtains the current operation from the iterator.
 //
 // https://www.sqlite.org/session/sqlite3changeset_op.html
-func (iter *ChangesetIterator) Operation() (*ChangesetOperation, error) {
-	if iter.ptr == 0 {
+func (ci *ChangesetIterator) Operation() (*ChangesetOperation, error) {
+	if ci.ptr == 0 {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: iterator finalized")
 	}
-	pzTab, err := malloc(iter.tls, ptrSize)
+	pzTab, err := malloc(ci.tls, ptrSize)
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pzTab)
-	pnCol, err := malloc(iter.tls, types.Size_t(unsafe.Sizeof(int32(0))))
+	defer libc.Xfree(ci.tls, pzTab)
+	pnCol, err := malloc(ci.tls, types.Size_t(unsafe.Sizeof(int32(0))))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pnCol)
-	pOp, err := malloc(iter.tls, types.Size_t(unsafe.Sizeof(int32(0))))
+	defer libc.Xfree(ci.tls, pnCol)
+	pOp, err := malloc(ci.tls, types.Size_t(unsafe.Sizeof(int32(0))))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pOp)
-	pbIndirect, err := malloc(iter.tls, types.Size_t(unsafe.Sizeof(int32(0))))
+	defer libc.Xfree(ci.tls, pOp)
+	pbIndirect, err := malloc(ci.tls, types.Size_t(unsafe.Sizeof(int32(0))))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pbIndirect)
-	res := ResultCode(lib.Xsqlite3changeset_op(iter.tls, iter.ptr, pzTab, pnCol, pOp, pbIndirect))
+	defer libc.Xfree(ci.tls, pbIndirect)
+	res := ResultCode(lib.Xsqlite3changeset_op(ci.tls, ci.ptr, pzTab, pnCol, pOp, pbIndirect))
 	if err := res.ToError(); err != nil {
 		return nil, fmt.Errorf("sqlite: changeset iterator operation: %w", err)
 	}
@@ -460,21 +492,21 @@ func (iter *ChangesetIterator) Operation() (*ChangesetOperation, error) {
 // The returned value is valid until the iterator is finalized.
 //
 // https://www.sqlite.org/session/sqlite3changeset_old.html
-func (iter *ChangesetIterator) Old(col int) (Value, error) {
-	if iter.ptr == 0 {
+func (ci *ChangesetIterator) Old(col int) (Value, error) {
+	if ci.ptr == 0 {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: iterator finalized")
 	}
-	ppValue, err := malloc(iter.tls, ptrSize)
+	ppValue, err := malloc(ci.tls, ptrSize)
 	if err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %v", err)
 	}
-	defer libc.Xfree(iter.tls, ppValue)
-	res := ResultCode(lib.Xsqlite3changeset_old(iter.tls, iter.ptr, int32(col), ppValue))
+	defer libc.Xfree(ci.tls, ppValue)
+	res := ResultCode(lib.Xsqlite3changeset_old(ci.tls, ci.ptr, int32(col), ppValue))
 	if err := res.ToError(); err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %w", err)
 	}
 	return Value{
-		tls:       iter.tls,
+		tls:       ci.tls,
 		ptrOrType: *(*uintptr)(unsafe.Pointer(ppValue)),
 	}, nil
 }
@@ -483,21 +515,21 @@ func (iter *ChangesetIterator) Old(col int) (Value, error) {
 // The returned value is valid until the iterator is finalized.
 //
 // https://www.sqlite.org/session/sqlite3changeset_new.html
-func (iter *ChangesetIterator) New(col int) (Value, error) {
-	if iter.ptr == 0 {
+func (ci *ChangesetIterator) New(col int) (Value, error) {
+	if ci.ptr == 0 {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: iterator finalized")
 	}
-	ppValue, err := malloc(iter.tls, ptrSize)
+	ppValue, err := malloc(ci.tls, ptrSize)
 	if err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %v", err)
 	}
-	defer libc.Xfree(iter.tls, ppValue)
-	res := ResultCode(lib.Xsqlite3changeset_new(iter.tls, iter.ptr, int32(col), ppValue))
+	defer libc.Xfree(ci.tls, ppValue)
+	res := ResultCode(lib.Xsqlite3changeset_new(ci.tls, ci.ptr, int32(col), ppValue))
 	if err := res.ToError(); err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %w", err)
 	}
 	return Value{
-		tls:       iter.tls,
+		tls:       ci.tls,
 		ptrOrType: *(*uintptr)(unsafe.Pointer(ppValue)),
 	}, nil
 }
@@ -507,21 +539,21 @@ func (iter *ChangesetIterator) New(col int) (Value, error) {
 // finalized.
 //
 // https://www.sqlite.org/session/sqlite3changeset_conflict.html
-func (iter *ChangesetIterator) ConflictValue(col int) (Value, error) {
-	if iter.ptr == 0 {
+func (ci *ChangesetIterator) ConflictValue(col int) (Value, error) {
+	if ci.ptr == 0 {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: iterator finalized")
 	}
-	ppValue, err := malloc(iter.tls, ptrSize)
+	ppValue, err := malloc(ci.tls, ptrSize)
 	if err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %v", err)
 	}
-	defer libc.Xfree(iter.tls, ppValue)
-	res := ResultCode(lib.Xsqlite3changeset_conflict(iter.tls, iter.ptr, int32(col), ppValue))
+	defer libc.Xfree(ci.tls, ppValue)
+	res := ResultCode(lib.Xsqlite3changeset_conflict(ci.tls, ci.ptr, int32(col), ppValue))
 	if err := res.ToError(); err != nil {
 		return Value{}, fmt.Errorf("sqlite: get changeset iterator value: %w", err)
 	}
 	return Value{
-		tls:       iter.tls,
+		tls:       ci.tls,
 		ptrOrType: *(*uintptr)(unsafe.Pointer(ppValue)),
 	}, nil
 }
@@ -529,13 +561,13 @@ func (iter *ChangesetIterator) ConflictValue(col int) (Value, error) {
 // ForeignKeyConflicts returns the number of foreign key constraint violations.
 //
 // https://www.sqlite.org/session/sqlite3changeset_fk_conflicts.html
-func (iter *ChangesetIterator) ForeignKeyConflicts() (int, error) {
-	pnOut, err := malloc(iter.tls, types.Size_t(unsafe.Sizeof(int32(0))))
+func (ci *ChangesetIterator) ForeignKeyConflicts() (int, error) {
+	pnOut, err := malloc(ci.tls, types.Size_t(unsafe.Sizeof(int32(0))))
 	if err != nil {
 		return 0, fmt.Errorf("sqlite: get number of foreign key conflicts: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pnOut)
-	res := ResultCode(lib.Xsqlite3changeset_fk_conflicts(iter.tls, iter.ptr, pnOut))
+	defer libc.Xfree(ci.tls, pnOut)
+	res := ResultCode(lib.Xsqlite3changeset_fk_conflicts(ci.tls, ci.ptr, pnOut))
 	if err := res.ToError(); err != nil {
 		return 0, fmt.Errorf("sqlite: get number of foreign key conflicts: %w", err)
 	}
@@ -545,18 +577,18 @@ func (iter *ChangesetIterator) ForeignKeyConflicts() (int, error) {
 // PrimaryKey returns a map of columns that make up the primary key.
 //
 // https://www.sqlite.org/session/sqlite3changeset_pk.html
-func (iter *ChangesetIterator) PrimaryKey() ([]bool, error) {
-	pabPK, err := malloc(iter.tls, ptrSize)
+func (ci *ChangesetIterator) PrimaryKey() ([]bool, error) {
+	pabPK, err := malloc(ci.tls, ptrSize)
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: get primary key columns: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pabPK)
-	pnCol, err := malloc(iter.tls, types.Size_t(unsafe.Sizeof(int32(0))))
+	defer libc.Xfree(ci.tls, pabPK)
+	pnCol, err := malloc(ci.tls, types.Size_t(unsafe.Sizeof(int32(0))))
 	if err != nil {
 		return nil, fmt.Errorf("sqlite: get primary key columns: %v", err)
 	}
-	defer libc.Xfree(iter.tls, pnCol)
-	res := ResultCode(lib.Xsqlite3changeset_pk(iter.tls, iter.ptr, pabPK, pnCol))
+	defer libc.Xfree(ci.tls, pnCol)
+	res := ResultCode(lib.Xsqlite3changeset_pk(ci.tls, ci.ptr, pabPK, pnCol))
 	if err := res.ToError(); err != nil {
 		return nil, fmt.Errorf("sqlite: get primary key columns: %w", err)
 	}
@@ -154,6 +154,48 @@ func TestChangeset(t *testing.T) {
 	}
 }
 
+func TestChangesetIteratorAll(t *testing.T) {
+	conn, s := fillSession(t)
+	defer func() {
+		s.Delete()
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	buf := new(bytes.Buffer)
+	if err := s.WriteChangeset(buf); err != nil {
+		t.Fatal(err)
+	}
+	iter, err := sqlite.NewChangesetIterator(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := iter.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	numInserts := 0
+	for op, err := range iter.All() {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Type != sqlite.OpInsert {
+			continue
+		}
+		numInserts++
+		if v, err := iter.New(0); err != nil {
+			t.Error(err)
+		} else if v.Type() == sqlite.TypeNull {
+			t.Error("inserted primary key is NULL")
+		}
+	}
+	if numInserts != 100 {
+		t.Errorf("num inserts=%d, want 100", numInserts)
+	}
+}
+
 func TestChangesetInvert(t *testing.T) {
 	conn, s := fillSession(t)
 	defer func() {
@@ -0,0 +1,88 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SnapshotTo writes a consistent copy of the connection's main database to w
+// in the SQLite database file format.
+// The copy is made with [VACUUM INTO] a temporary file in [os.TempDir],
+// so the database is never held in memory
+// and the output is compacted and free of unused pages.
+// Other connections may continue to read and write the database
+// while the snapshot is being made.
+// The connection must not have an open transaction.
+// SnapshotTo returns the number of bytes written to w.
+//
+// The temporary file is created through the database's [VFS],
+// so SnapshotTo does not work on databases opened with a VFS
+// that cannot create files in [os.TempDir].
+//
+// If ctx is done before the snapshot completes,
+// SnapshotTo interrupts the operation and returns ctx.Err().
+// Any interrupt set with [Conn.SetInterrupt] is restored before SnapshotTo returns.
+//
+// [VACUUM INTO]: https://sqlite.org/lang_vacuum.html#vacuuminto
+func (c *Conn) SnapshotTo(ctx context.Context, w io.Writer) (n int64, err error) {
+	if c == nil {
+		return 0, fmt.Errorf("sqlite: snapshot: nil connection")
+	}
+	dir, err := os.MkdirTemp("", "zombiezen-sqlite-snapshot")
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: snapshot: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "snapshot.db")
+
+	if err := c.vacuumInto(ctx, path); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return 0, fmt.Errorf("sqlite: snapshot: %w", ctxErr)
+		}
+		return 0, fmt.Errorf("sqlite: snapshot: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: snapshot: %v", err)
+	}
+	defer f.Close()
+	n, err = io.Copy(w, ctxReader{ctx, f})
+	if err != nil {
+		return n, fmt.Errorf("sqlite: snapshot: %w", err)
+	}
+	return n, nil
+}
+
+func (c *Conn) vacuumInto(ctx context.Context, path string) error {
+	oldDoneCh := c.SetInterrupt(ctx.Done())
+	defer c.SetInterrupt(oldDoneCh)
+
+	stmt, _, err := c.PrepareTransient("VACUUM INTO ?;")
+	if err != nil {
+		return err
+	}
+	defer stmt.Finalize()
+	stmt.BindText(1, path)
+	_, err = stmt.Step()
+	return err
+}
+
+// ctxReader is an [io.Reader] that stops reading once its context is done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (cr ctxReader) Read(p []byte) (int, error) {
+	if err := cr.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return cr.r.Read(p)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestSnapshotTo(t *testing.T) {
+	c, err := sqlite.OpenConn(filepath.Join(t.TempDir(), "snapshot.db"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = sqlitex.ExecuteScript(c, `
+		CREATE TABLE foo (x INTEGER);
+		INSERT INTO foo VALUES (1), (2), (3);
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	n, err := c.SnapshotTo(context.Background(), buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("SnapshotTo(...) = %d, <nil>; wrote %d bytes", n, buf.Len())
+	}
+
+	restored, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restored.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := restored.Deserialize("main", buf.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	sum, err := sqlitex.ResultInt(restored.Prep("SELECT sum(x) FROM foo;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 {
+		t.Errorf("sum(x) = %d; want 6", sum)
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		buf := new(bytes.Buffer)
+		if _, err := c.SnapshotTo(ctx, buf); !errors.Is(err, context.Canceled) {
+			t.Errorf("SnapshotTo(...) = _, %v; want %v", err, context.Canceled)
+		}
+		if buf.Len() > 0 {
+			t.Errorf("SnapshotTo wrote %d bytes", buf.Len())
+		}
+		// Connection should still be usable.
+		if _, err := sqlitex.ResultInt(c.Prep("SELECT count(*) FROM foo;")); err != nil {
+			t.Error(err)
+		}
+	})
+}
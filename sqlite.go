@@ -19,6 +19,8 @@ package sqlite
 
 import (
 	"bytes"
+	"container/list"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -56,6 +58,14 @@ type Conn struct {
 	stmts  map[string]*Stmt // query -> prepared statement
 	closed bool
 
+	stmtLRU        list.List // of *Stmt in stmts, most recently used first
+	stmtCacheSize  int
+	stmtCacheStats StmtCacheStats
+
+	timeFormat TimeFormat
+
+	liveStmts map[uintptr]*Stmt // sqlite3_stmt* -> Stmt, including transient statements
+
 	cancelCh   chan struct{}
 	doneCh     <-chan struct{}
 	unlockNote uintptr
@@ -188,6 +198,7 @@ func openConn(path string, openFlags OpenFlags) (_ *Conn, err error) {
 		tls:        tls,
 		conn:       *(*uintptr)(unsafe.Pointer(connPtr)),
 		stmts:      make(map[string]*Stmt),
+		liveStmts:  make(map[uintptr]*Stmt),
 		unlockNote: unlockNote,
 	}
 	if c.conn == 0 {
@@ -216,7 +227,10 @@ func openConn(path string, openFlags OpenFlags) (_ *Conn, err error) {
 }
 
 // Close closes the database connection using sqlite3_close and finalizes
-// persistent prepared statements. https://www.sqlite.org/c3ref/close.html
+// persistent prepared statements
+// as well as any statements from [Conn.PrepareTransient]
+// that have not been finalized.
+// https://www.sqlite.org/c3ref/close.html
 func (c *Conn) Close() error {
 	if c == nil {
 		return fmt.Errorf("sqlite: close: nil connection")
@@ -229,13 +243,18 @@ func (c *Conn) Close() error {
 	for _, stmt := range c.stmts {
 		stmt.Finalize()
 	}
+	for _, stmt := range c.liveStmts {
+		stmt.Finalize()
+	}
 	res := ResultCode(lib.Xsqlite3_close(c.tls, c.conn))
 	libc.Xfree(c.tls, c.unlockNote)
 	c.unlockNote = 0
 	c.tls.Close()
 	c.tls = nil
 	c.releaseAuthorizer()
+	c.releaseHooks()
 	busyHandlers.Delete(c.conn)
+	progressHandlers.Delete(c.conn)
 	allConns.mu.Lock()
 	delete(allConns.table, c.conn)
 	allConns.mu.Unlock()
@@ -313,6 +332,36 @@ func (c *Conn) SetInterrupt(doneCh <-chan struct{}) (oldDoneCh <-chan struct{})
 	return oldDoneCh
 }
 
+// IsInterrupted reports whether an interrupt is currently in effect
+// for the connection.
+// An interrupt remains in effect until all of the connection's
+// running statements have finished.
+//
+// https://www.sqlite.org/c3ref/interrupt.html
+func (c *Conn) IsInterrupted() bool {
+	if c == nil || c.closed {
+		return false
+	}
+	return lib.Xsqlite3_is_interrupted(c.tls, c.conn) != 0
+}
+
+// HasBusyStmt reports whether any statement on the connection,
+// including statements created with [Conn.PrepareTransient],
+// has been stepped but not run to completion or reset.
+//
+// https://www.sqlite.org/c3ref/stmt_busy.html
+func (c *Conn) HasBusyStmt() bool {
+	if c == nil || c.closed {
+		return false
+	}
+	for stmt := lib.Xsqlite3_next_stmt(c.tls, c.conn, 0); stmt != 0; stmt = lib.Xsqlite3_next_stmt(c.tls, c.conn, stmt) {
+		if lib.Xsqlite3_stmt_busy(c.tls, stmt) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // SetBusyTimeout sets a busy handler that sleeps for up to d to acquire a lock.
 // Passing a non-positive value will turn off all busy handlers.
 //
@@ -398,6 +447,50 @@ func busyHandlerCallback(tls *libc.TLS, pArg uintptr, count int32) int32 {
 	return 1
 }
 
+// SetProgressHandler registers a function that is called periodically
+// during long-running calls to [Stmt.Step] and similar methods,
+// approximately every nOps virtual machine instructions.
+// If fn returns false, then the operation is interrupted
+// and the call returns a [ResultInterrupt] error.
+// This can be used to report progress or to limit the amount of work
+// a single statement may perform.
+// The progress handler runs independently of [Conn.SetInterrupt]:
+// either one may interrupt an operation.
+// fn must not modify the database connection,
+// including by preparing or running statements.
+//
+// Passing a non-positive nOps or a nil fn removes any progress handler.
+//
+// https://sqlite.org/c3ref/progress_handler.html
+func (c *Conn) SetProgressHandler(nOps int, fn func() bool) {
+	if c == nil {
+		return
+	}
+	if nOps <= 0 || fn == nil {
+		lib.Xsqlite3_progress_handler(c.tls, c.conn, 0, 0, 0)
+		progressHandlers.Delete(c.conn)
+		return
+	}
+	progressHandlers.Store(c.conn, fn)
+	xProgress := cFuncPointer(progressHandlerCallback)
+	lib.Xsqlite3_progress_handler(c.tls, c.conn, int32(nOps), xProgress, c.conn)
+}
+
+var progressHandlers sync.Map // sqlite3* -> func() bool
+
+func progressHandlerCallback(tls *libc.TLS, pArg uintptr) int32 {
+	val, _ := progressHandlers.Load(pArg)
+	if val == nil {
+		return 0
+	}
+	f := val.(func() bool)
+	if !f() {
+		// A non-zero return interrupts the operation.
+		return 1
+	}
+	return 0
+}
+
 func (c *Conn) interrupted() error {
 	select {
 	case <-c.doneCh:
@@ -422,6 +515,9 @@ func (c *Conn) cancelInterrupt() {
 // Persistent prepared statements are cached by the query
 // string in a Conn. If Finalize is not called, then subsequent
 // calls to Prepare will return the same statement.
+// If the cache has a limit set by [Conn.SetStmtCacheSize],
+// the statement may be finalized by a later call to Prep or Prepare
+// once it has been reset or stepped to completion.
 //
 // https://www.sqlite.org/c3ref/prepare.html
 func (c *Conn) Prep(query string) *Stmt {
@@ -445,6 +541,9 @@ func (c *Conn) Prep(query string) *Stmt {
 // Persistent prepared statements are cached by the query
 // string in a Conn. If Finalize is not called, then subsequent
 // calls to Prepare will return the same statement.
+// If the cache has a limit set by [Conn.SetStmtCacheSize],
+// the statement may be finalized by a later call to Prep or Prepare
+// once it has been reset or stepped to completion.
 //
 // If the query has any unprocessed trailing bytes, Prepare
 // returns an error.
@@ -455,12 +554,15 @@ func (c *Conn) Prepare(query string) (*Stmt, error) {
 		return nil, fmt.Errorf("sqlite: prepare %q: nil connection", query)
 	}
 	if stmt := c.stmts[query]; stmt != nil {
+		c.stmtCacheStats.Hits++
+		c.stmtLRU.MoveToFront(stmt.lruElem)
 		if err := stmt.Reset(); err != nil {
 			return nil, err
 		}
 		if err := stmt.ClearBindings(); err != nil {
 			return nil, err
 		}
+		stmt.pinned = true
 		return stmt, nil
 	}
 	stmt, trailingBytes, err := c.prepare(query, lib.SQLITE_PREPARE_PERSISTENT)
@@ -471,7 +573,11 @@ func (c *Conn) Prepare(query string) (*Stmt, error) {
 		stmt.Finalize()
 		return nil, fmt.Errorf("sqlite: prepare %q: statement has trailing bytes", query)
 	}
+	c.stmtCacheStats.Misses++
 	c.stmts[query] = stmt
+	stmt.lruElem = c.stmtLRU.PushFront(stmt)
+	stmt.pinned = true
+	c.evictStmts()
 	return stmt, nil
 }
 
@@ -547,6 +653,9 @@ func (c *Conn) prepare(query string, flags uint32) (*Stmt, int, error) {
 			stmt.bindNames[i] = libc.GoString(cname)
 		}
 	}
+	if stmt.stmt != 0 {
+		c.liveStmts[stmt.stmt] = stmt
+	}
 
 	colCount := int(lib.Xsqlite3_column_count(c.tls, stmt.stmt))
 	stmt.colNames = make(map[string]int, colCount)
@@ -690,10 +799,15 @@ type Stmt struct {
 	bindNames     []string
 	colNames      map[string]int
 	bindErr       error
-	prepInterrupt bool // set if Prep was interrupted
-	lastHasRow    bool // last bool returned by Step
+	prepInterrupt bool          // set if Prep was interrupted
+	lastHasRow    bool          // last bool returned by Step
+	lruElem       *list.Element // element in conn.stmtLRU if cached
+	pinned        bool          // returned by Prepare and not reset or stepped to completion since
 }
 
+// errFinalized is returned from methods called on a finalized statement.
+var errFinalized = errors.New("statement has been finalized")
+
 func (stmt *Stmt) interrupted() error {
 	if stmt.prepInterrupt {
 		return ResultInterrupt.ToError()
@@ -709,13 +823,25 @@ func (stmt *Stmt) interrupted() error {
 // Do not call Finalize on a prepared statement that
 // you intend to prepare again in the future.
 //
+// Once a statement is finalized, its Reset, ClearBindings, and Step methods
+// return an error, its Bind methods do nothing,
+// its Column methods return zero values,
+// and further calls to Finalize do nothing.
+//
 // https://www.sqlite.org/c3ref/finalize.html
 func (stmt *Stmt) Finalize() error {
+	if stmt.conn == nil {
+		return nil
+	}
 	if ptr := stmt.conn.stmts[stmt.query]; ptr == stmt {
 		delete(stmt.conn.stmts, stmt.query)
+		stmt.conn.stmtLRU.Remove(stmt.lruElem)
+		stmt.lruElem = nil
 	}
+	delete(stmt.conn.liveStmts, stmt.stmt)
 	res := ResultCode(lib.Xsqlite3_finalize(stmt.conn.tls, stmt.stmt))
 	stmt.conn = nil
+	stmt.stmt = 0
 	if err := res.ToError(); err != nil {
 		return fmt.Errorf("sqlite: finalize: %w", err)
 	}
@@ -729,7 +855,11 @@ func (stmt *Stmt) Finalize() error {
 //
 // https://www.sqlite.org/c3ref/reset.html
 func (stmt *Stmt) Reset() error {
+	if stmt.conn == nil {
+		return fmt.Errorf("sqlite: reset: %w", errFinalized)
+	}
 	stmt.lastHasRow = false
+	stmt.pinned = false
 	var res ResultCode
 	for {
 		if err := stmt.interrupted(); err != nil {
@@ -756,6 +886,9 @@ func (stmt *Stmt) Reset() error {
 //
 // https://www.sqlite.org/c3ref/clear_bindings.html
 func (stmt *Stmt) ClearBindings() error {
+	if stmt.conn == nil {
+		return fmt.Errorf("sqlite: clear bindings: %w", errFinalized)
+	}
 	if err := stmt.interrupted(); err != nil {
 		return fmt.Errorf("sqlite: clear bindings: %w", err)
 	}
@@ -799,6 +932,9 @@ func (stmt *Stmt) ClearBindings() error {
 //
 //	http://www.sqlite.org/unlock_notify.html
 func (stmt *Stmt) Step() (rowReturned bool, err error) {
+	if stmt.conn == nil {
+		return false, fmt.Errorf("sqlite: step: %w", errFinalized)
+	}
 	if stmt.bindErr != nil {
 		err = stmt.bindErr
 		stmt.bindErr = nil
@@ -807,6 +943,11 @@ func (stmt *Stmt) Step() (rowReturned bool, err error) {
 	}
 	rowReturned, err = stmt.step()
 	stmt.lastHasRow = rowReturned
+	if !rowReturned {
+		// The statement is done (or reset below),
+		// so the cache may evict it.
+		stmt.pinned = false
+	}
 	if err != nil {
 		lib.Xsqlite3_reset(stmt.conn.tls, stmt.stmt)
 		return rowReturned, fmt.Errorf("sqlite: step: %w", err)
@@ -856,6 +997,9 @@ func (stmt *Stmt) handleBindErr(prefix string, res ResultCode) {
 //
 // https://www.sqlite.org/c3ref/data_count.html
 func (stmt *Stmt) DataCount() int {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return int(lib.Xsqlite3_data_count(stmt.conn.tls, stmt.stmt))
 }
 
@@ -864,6 +1008,9 @@ func (stmt *Stmt) DataCount() int {
 //
 // https://www.sqlite.org/c3ref/column_count.html
 func (stmt *Stmt) ColumnCount() int {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return int(lib.Xsqlite3_column_count(stmt.conn.tls, stmt.stmt))
 }
 
@@ -1107,6 +1254,9 @@ func (stmt *Stmt) ColumnInt(col int) int {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnInt32(col int) int32 {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return lib.Xsqlite3_column_int(stmt.conn.tls, stmt.stmt, int32(col))
 }
 
@@ -1116,6 +1266,9 @@ func (stmt *Stmt) ColumnInt32(col int) int32 {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnInt64(col int) int64 {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return lib.Xsqlite3_column_int64(stmt.conn.tls, stmt.stmt, int32(col))
 }
 
@@ -1149,6 +1302,9 @@ func (stmt *Stmt) ColumnReader(col int) *bytes.Reader {
 }
 
 func (stmt *Stmt) columnBytes(col int) []byte {
+	if stmt.stmt == 0 {
+		return nil
+	}
 	p := lib.Xsqlite3_column_blob(stmt.conn.tls, stmt.stmt, int32(col))
 	if p == 0 {
 		return nil
@@ -1208,6 +1364,9 @@ func (t ColumnType) String() string {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnType(col int) ColumnType {
+	if stmt.stmt == 0 {
+		return TypeNull
+	}
 	return ColumnType(lib.Xsqlite3_column_type(stmt.conn.tls, stmt.stmt, int32(col)))
 }
 
@@ -1223,6 +1382,9 @@ func (stmt *Stmt) ColumnIsNull(col int) bool {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnText(col int) string {
+	if stmt.stmt == 0 {
+		return ""
+	}
 	n := stmt.ColumnLen(col)
 	return goStringN(lib.Xsqlite3_column_text(stmt.conn.tls, stmt.stmt, int32(col)), n)
 }
@@ -1233,6 +1395,9 @@ func (stmt *Stmt) ColumnText(col int) string {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnFloat(col int) float64 {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return lib.Xsqlite3_column_double(stmt.conn.tls, stmt.stmt, int32(col))
 }
 
@@ -1242,14 +1407,23 @@ func (stmt *Stmt) ColumnFloat(col int) float64 {
 //
 // https://www.sqlite.org/c3ref/column_blob.html
 func (stmt *Stmt) ColumnLen(col int) int {
+	if stmt.stmt == 0 {
+		return 0
+	}
 	return int(lib.Xsqlite3_column_bytes(stmt.conn.tls, stmt.stmt, int32(col)))
 }
 
 func (stmt *Stmt) ColumnDatabaseName(col int) string {
+	if stmt.stmt == 0 {
+		return ""
+	}
 	return libc.GoString(lib.Xsqlite3_column_database_name(stmt.conn.tls, stmt.stmt, int32(col)))
 }
 
 func (stmt *Stmt) ColumnTableName(col int) string {
+	if stmt.stmt == 0 {
+		return ""
+	}
 	return libc.GoString(lib.Xsqlite3_column_table_name(stmt.conn.tls, stmt.stmt, int32(col)))
 }
 
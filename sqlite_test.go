@@ -263,6 +263,63 @@ INSERT INTO resetint (c) VALUES (3);`)
 	stmt.Reset()
 }
 
+func TestProgressHandler(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	const query = "WITH RECURSIVE cnt(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM cnt LIMIT 100000) SELECT count(*) FROM cnt;"
+
+	t.Run("Continue", func(t *testing.T) {
+		calls := 0
+		c.SetProgressHandler(100, func() bool {
+			calls++
+			return true
+		})
+		defer c.SetProgressHandler(0, nil)
+
+		n, err := sqlitex.ResultInt(c.Prep(query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != 100000 {
+			t.Errorf("count(*) = %d; want 100000", n)
+		}
+		if calls == 0 {
+			t.Error("progress handler not called")
+		}
+	})
+
+	t.Run("Abort", func(t *testing.T) {
+		calls := 0
+		c.SetProgressHandler(100, func() bool {
+			calls++
+			return calls < 10
+		})
+		defer c.SetProgressHandler(0, nil)
+
+		_, err := sqlitex.ResultInt(c.Prep(query))
+		if got, want := sqlite.ErrCode(err), sqlite.ResultInterrupt; got != want {
+			t.Errorf("sqlite.ErrCode(err) = %v; want %v (err = %v)", got, want, err)
+		}
+		if calls != 10 {
+			t.Errorf("progress handler called %d times; want 10", calls)
+		}
+
+		// Connection should be usable once handler is removed.
+		c.SetProgressHandler(0, nil)
+		if _, err := sqlitex.ResultInt(c.Prep(query)); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
 func TestTrailingBytes(t *testing.T) {
 	conn, err := sqlite.OpenConn(":memory:", 0)
 	if err != nil {
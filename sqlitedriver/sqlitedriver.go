@@ -0,0 +1,681 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+// Package sqlitedriver provides an opt-in [database/sql] driver
+// built on [sqlite.Conn] and [sqlitex.Pool].
+//
+// The sqlite package deliberately does not use database/sql,
+// but some third-party libraries (e.g. migration tools or ORMs)
+// only accept a [*sql.DB].
+// This package allows such libraries to share a database
+// with code that uses the sqlite package directly:
+//
+//	pool, err := sqlitex.NewPool("foo.db", sqlitex.PoolOptions{})
+//	if err != nil {
+//		return err
+//	}
+//	db := sql.OpenDB(sqlitedriver.NewPoolConnector(pool))
+//	defer pool.Close()
+//	defer db.Close()
+//
+// The driver keeps the semantics of the sqlite package:
+//
+//   - Statements are prepared with [sqlite.Conn.Prepare],
+//     so they are cached on the connection
+//     and shared with code that uses the sqlite package directly.
+//     A query that already has an open statement on the connection
+//     is prepared with [sqlite.Conn.PrepareTransient] instead.
+//   - Contexts passed to database/sql methods
+//     interrupt running statements via [sqlite.Conn.SetInterrupt].
+//   - Transactions are implemented with SAVEPOINT,
+//     so they nest inside transactions started with the sqlite package
+//     (e.g. with [sqlitex.Save]) on a connection obtained via [sql.Conn.Raw].
+//
+// The driver is not registered with [sql.Register]:
+// use [sql.OpenDB] with a [Connector],
+// or register [Driver] under a name of your choosing.
+package sqlitedriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+// Driver is a [driver.Driver] that opens connections with [sqlite.OpenConn]
+// using the default flags.
+// The data source name is passed to [sqlite.OpenConn] as the path.
+type Driver struct{}
+
+// Open opens a new connection to the database named by name.
+func (Driver) Open(name string) (driver.Conn, error) {
+	return NewConnector(name, 0).Connect(context.Background())
+}
+
+// OpenConnector returns a [Connector] for the database named by name.
+func (Driver) OpenConnector(name string) (driver.Connector, error) {
+	return NewConnector(name, 0), nil
+}
+
+// Connector is a [driver.Connector] that provides [*sqlite.Conn] values
+// to [database/sql].
+type Connector struct {
+	take func(ctx context.Context) (*sqlite.Conn, error)
+	put  func(conn *sqlite.Conn) error
+}
+
+// NewConnector returns a [Connector]
+// that opens a new connection with [sqlite.OpenConn]
+// each time database/sql needs one.
+// A flags value of 0 uses the same defaults as [sqlite.OpenConn].
+func NewConnector(path string, flags sqlite.OpenFlags) *Connector {
+	return &Connector{
+		take: func(ctx context.Context) (*sqlite.Conn, error) {
+			return sqlite.OpenConn(path, flags)
+		},
+		put: func(conn *sqlite.Conn) error {
+			return conn.Close()
+		},
+	}
+}
+
+// NewPoolConnector returns a [Connector]
+// that takes connections from pool
+// and returns them to pool when database/sql closes them.
+// Because database/sql keeps idle connections open,
+// consider calling [sql.DB.SetMaxIdleConns]
+// to limit how many connections it holds from pool.
+// The [sql.DB] must be closed before pool is closed.
+func NewPoolConnector(pool *sqlitex.Pool) *Connector {
+	return &Connector{
+		take: func(ctx context.Context) (*sqlite.Conn, error) {
+			conn, err := pool.Take(ctx)
+			if err != nil {
+				return nil, err
+			}
+			// Interrupts are set for each operation,
+			// not for the lifetime of the driver connection.
+			conn.SetInterrupt(nil)
+			return conn, nil
+		},
+		put: func(conn *sqlite.Conn) error {
+			pool.Put(conn)
+			return nil
+		},
+	}
+}
+
+// Connect returns a new connection.
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.take(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("sqlitedriver: connect: %w", err)
+	}
+	interrupt := make(chan struct{})
+	conn.SetInterrupt(interrupt)
+	return &Conn{
+		conn:      conn,
+		put:       c.put,
+		inUse:     make(map[string]int),
+		stmts:     make(map[*Stmt]struct{}),
+		rows:      make(map[*rows]struct{}),
+		interrupt: interrupt,
+	}, nil
+}
+
+// Driver returns a [Driver].
+func (c *Connector) Driver() driver.Driver {
+	return Driver{}
+}
+
+// Conn is a [driver.Conn] backed by a [*sqlite.Conn].
+// Use [sql.Conn.Raw] to access it.
+type Conn struct {
+	conn *sqlite.Conn
+	put  func(conn *sqlite.Conn) error
+
+	// inUse counts the driver statements using each cached query.
+	// A query that is already in use is prepared as a transient statement,
+	// since the cached statement may have open rows.
+	inUse map[string]int
+	// stmts is the set of statements that have not been closed.
+	// They are closed when the connection is closed.
+	stmts map[*Stmt]struct{}
+	// rows is the set of rows that have not been closed.
+	rows map[*rows]struct{}
+	// interrupt is the channel passed to conn.SetInterrupt.
+	// It is closed when the context of the running operation is done.
+	// Changing the interrupt resets the connection's busy cached statements,
+	// so it is only replaced after it has been closed.
+	interrupt chan struct{}
+	// readOnly is set while a read-only transaction is open.
+	readOnly bool
+}
+
+var (
+	_ driver.Conn               = (*Conn)(nil)
+	_ driver.ConnBeginTx        = (*Conn)(nil)
+	_ driver.ConnPrepareContext = (*Conn)(nil)
+	_ driver.ExecerContext      = (*Conn)(nil)
+	_ driver.Pinger             = (*Conn)(nil)
+	_ driver.SessionResetter    = (*Conn)(nil)
+	_ driver.Validator          = (*Conn)(nil)
+)
+
+// SQLiteConn returns the underlying connection.
+// The returned connection must not be used after the function passed to
+// [sql.Conn.Raw] returns.
+func (c *Conn) SQLiteConn() *sqlite.Conn {
+	return c.conn
+}
+
+// Prepare returns a prepared statement, bound to this connection.
+func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext returns a prepared statement, bound to this connection.
+func (c *Conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	// Conn.Prepare rejects any trailing bytes, including whitespace.
+	query = strings.TrimSpace(query)
+	var s *Stmt
+	if c.inUse[query] > 0 {
+		stmt, trailingBytes, err := c.conn.PrepareTransient(query)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(query[len(query)-trailingBytes:]) != "" {
+			stmt.Finalize()
+			return nil, fmt.Errorf("sqlitedriver: prepare %q: statement has trailing bytes", query)
+		}
+		s = &Stmt{conn: c, transient: stmt, numInput: stmt.BindParamCount()}
+	} else {
+		stmt, err := c.conn.Prepare(query)
+		if err != nil {
+			return nil, err
+		}
+		// The cached statement may be evicted once it is reset,
+		// so each execution prepares it again.
+		s = &Stmt{conn: c, query: query, numInput: stmt.BindParamCount()}
+		c.inUse[query]++
+	}
+	c.stmts[s] = struct{}{}
+	return s, nil
+}
+
+// ExecContext executes a query without preparing a driver statement.
+// If args is empty, then query may contain multiple statements,
+// which are executed in order.
+func (c *Conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) > 0 {
+		return nil, driver.ErrSkip
+	}
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	for {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			break
+		}
+		stmt, trailingBytes, err := c.conn.PrepareTransient(query)
+		if err != nil {
+			return nil, err
+		}
+		query = query[len(query)-trailingBytes:]
+		err = stepToEnd(stmt)
+		stmt.Finalize()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.result(), nil
+}
+
+func (c *Conn) result() driver.Result {
+	return result{
+		lastInsertID: c.conn.LastInsertRowID(),
+		rowsAffected: int64(c.conn.Changes()),
+	}
+}
+
+// savepointName is the name of the savepoint used for database/sql transactions.
+const savepointName = "sqlitedriver.Tx"
+
+// Begin starts and returns a new transaction.
+//
+// Deprecated: Use [Conn.BeginTx] instead.
+func (c *Conn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx starts and returns a new transaction using SAVEPOINT.
+// SQLite transactions are always serializable,
+// so only the default and serializable isolation levels are supported.
+// Read-only transactions set the [query_only pragma]
+// for the duration of the transaction.
+//
+// [query_only pragma]: https://sqlite.org/pragma.html#pragma_query_only
+func (c *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("sqlitedriver: begin: unsupported isolation level %v", sql.IsolationLevel(opts.Isolation))
+	}
+	end, err := c.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+
+	if opts.ReadOnly && !c.readOnly {
+		if err := sqlitex.ExecuteTransient(c.conn, "PRAGMA query_only = true;", nil); err != nil {
+			return nil, fmt.Errorf("sqlitedriver: begin: %w", err)
+		}
+		c.readOnly = true
+	}
+	if err := sqlitex.ExecuteTransient(c.conn, fmt.Sprintf("SAVEPOINT %q;", savepointName), nil); err != nil {
+		c.endReadOnly()
+		return nil, fmt.Errorf("sqlitedriver: begin: %w", err)
+	}
+	return &tx{c}, nil
+}
+
+func (c *Conn) endReadOnly() {
+	if c.readOnly {
+		sqlitex.ExecuteTransient(c.conn, "PRAGMA query_only = false;", nil)
+		c.readOnly = false
+	}
+}
+
+// Ping verifies the connection is still usable.
+func (c *Conn) Ping(ctx context.Context) error {
+	if c.conn == nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// ResetSession is called by database/sql
+// before the connection is reused for another request.
+func (c *Conn) ResetSession(ctx context.Context) error {
+	if c.conn == nil {
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// IsValid reports whether the connection can be reused by database/sql.
+func (c *Conn) IsValid() bool {
+	return c.conn != nil && c.conn.AutocommitEnabled()
+}
+
+// Close returns the connection to its pool or closes it.
+func (c *Conn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	for r := range c.rows {
+		r.Close()
+	}
+	for s := range c.stmts {
+		s.Close()
+	}
+	conn := c.conn
+	c.conn = nil
+	if err := c.put(conn); err != nil {
+		return fmt.Errorf("sqlitedriver: close: %w", err)
+	}
+	return nil
+}
+
+// begin prepares the connection for an operation that uses ctx.
+// The returned function must be called once the operation is complete.
+func (c *Conn) begin(ctx context.Context) (end func(), err error) {
+	if c.conn == nil {
+		return nil, driver.ErrBadConn
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return c.watch(ctx), nil
+}
+
+// watch interrupts the connection if ctx is done
+// before the returned function is called.
+func (c *Conn) watch(ctx context.Context) (stop func()) {
+	interrupt := c.interrupt
+	stopFunc := context.AfterFunc(ctx, func() { close(interrupt) })
+	return func() {
+		if stopFunc() {
+			return
+		}
+		// The interrupt has been closed (or is about to be),
+		// so replace it for the next operation.
+		// Replacing it resets the cached statements of any open rows,
+		// so those rows can no longer continue.
+		c.interrupt = make(chan struct{})
+		c.conn.SetInterrupt(c.interrupt)
+		for r := range c.rows {
+			if r.cached && r.err == nil {
+				r.err = sqlite.ResultInterrupt.ToError()
+			}
+		}
+	}
+}
+
+// Stmt is a [driver.Stmt] backed by a [*sqlite.Stmt].
+type Stmt struct {
+	conn *Conn
+	// transient is the statement from [sqlite.Conn.PrepareTransient]
+	// or nil if the statement uses the connection's statement cache.
+	transient *sqlite.Stmt
+	query     string // cached query if transient is nil
+	numInput  int
+	closed    bool
+}
+
+var (
+	_ driver.Stmt             = (*Stmt)(nil)
+	_ driver.StmtExecContext  = (*Stmt)(nil)
+	_ driver.StmtQueryContext = (*Stmt)(nil)
+)
+
+// NumInput returns the number of placeholder parameters.
+func (s *Stmt) NumInput() int {
+	return s.numInput
+}
+
+// Exec executes a query that doesn't return rows.
+//
+// Deprecated: Use [Stmt.ExecContext] instead.
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), valuesToNamed(args))
+}
+
+// ExecContext executes a query that doesn't return rows.
+func (s *Stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	end, err := s.conn.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	stmt, err := s.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	if err := stepToEnd(stmt); err != nil {
+		return nil, err
+	}
+	return s.conn.result(), nil
+}
+
+// Query executes a query that may return rows.
+//
+// Deprecated: Use [Stmt.QueryContext] instead.
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), valuesToNamed(args))
+}
+
+// QueryContext executes a query that may return rows.
+func (s *Stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	end, err := s.conn.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer end()
+	stmt, err := s.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	r := &rows{
+		conn:   s.conn,
+		stmt:   stmt,
+		cached: s.transient == nil,
+		ctx:    ctx,
+	}
+	s.conn.rows[r] = struct{}{}
+	return r, nil
+}
+
+// Close releases the statement.
+// Transient statements are finalized,
+// and cached statements remain prepared on the connection.
+func (s *Stmt) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	delete(s.conn.stmts, s)
+	if s.transient == nil {
+		if s.conn.inUse[s.query]--; s.conn.inUse[s.query] <= 0 {
+			delete(s.conn.inUse, s.query)
+		}
+		return nil
+	}
+	if s.conn.conn == nil {
+		return nil
+	}
+	return s.transient.Finalize()
+}
+
+// prepare returns the statement to execute, reset and with no bindings.
+func (s *Stmt) prepare() (*sqlite.Stmt, error) {
+	if s.transient == nil {
+		return s.conn.conn.Prepare(s.query)
+	}
+	if err := s.transient.Reset(); err != nil {
+		return nil, err
+	}
+	if err := s.transient.ClearBindings(); err != nil {
+		return nil, err
+	}
+	return s.transient, nil
+}
+
+func (s *Stmt) bind(args []driver.NamedValue) (*sqlite.Stmt, error) {
+	stmt, err := s.prepare()
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args {
+		i := arg.Ordinal
+		if arg.Name != "" {
+			i = paramIndex(stmt, arg.Name)
+			if i == 0 {
+				return nil, fmt.Errorf("sqlitedriver: unknown parameter %s", arg.Name)
+			}
+		}
+		switch v := arg.Value.(type) {
+		case nil:
+			stmt.BindNull(i)
+		case int64:
+			stmt.BindInt64(i, v)
+		case float64:
+			stmt.BindFloat(i, v)
+		case bool:
+			stmt.BindBool(i, v)
+		case []byte:
+			stmt.BindBytes(i, v)
+		case string:
+			stmt.BindText(i, v)
+		case time.Time:
+			stmt.BindTime(i, v)
+		default:
+			return nil, fmt.Errorf("sqlitedriver: unsupported type %T for parameter %d", v, i)
+		}
+	}
+	return stmt, nil
+}
+
+// paramIndex returns the index of the parameter with the given name
+// (without its prefix character) or 0 if there is no such parameter.
+func paramIndex(stmt *sqlite.Stmt, name string) int {
+	for i, n := 1, stmt.BindParamCount(); i <= n; i++ {
+		if pname := stmt.BindParamName(i); len(pname) > 1 && pname[1:] == name {
+			return i
+		}
+	}
+	return 0
+}
+
+type rows struct {
+	conn   *Conn
+	stmt   *sqlite.Stmt
+	cached bool
+	ctx    context.Context
+	// err is set if the rows' statement was reset
+	// because another operation on the connection was interrupted.
+	err    error
+	closed bool
+}
+
+func (r *rows) Columns() []string {
+	names := make([]string, r.stmt.ColumnCount())
+	for i := range names {
+		names[i] = r.stmt.ColumnName(i)
+	}
+	return names
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.ctx.Err(); err != nil {
+		return err
+	}
+	// Other operations may have run on the connection since the last row,
+	// so step with the interrupt for this set of rows.
+	end := r.conn.watch(r.ctx)
+	hasRow, err := r.stmt.Step()
+	end()
+	if err != nil {
+		return err
+	}
+	if !hasRow {
+		return io.EOF
+	}
+	for i := range dest {
+		switch r.stmt.ColumnType(i) {
+		case sqlite.TypeInteger:
+			dest[i] = r.stmt.ColumnInt64(i)
+		case sqlite.TypeFloat:
+			dest[i] = r.stmt.ColumnFloat(i)
+		case sqlite.TypeText:
+			dest[i] = r.stmt.ColumnText(i)
+		case sqlite.TypeBlob:
+			buf := make([]byte, r.stmt.ColumnLen(i))
+			r.stmt.ColumnBytes(i, buf)
+			dest[i] = buf
+		default:
+			dest[i] = nil
+		}
+	}
+	return nil
+}
+
+func (r *rows) Close() error {
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	delete(r.conn.rows, r)
+	if r.conn.conn == nil || r.err != nil {
+		return nil
+	}
+	return r.stmt.Reset()
+}
+
+type tx struct {
+	conn *Conn
+}
+
+func (t *tx) Commit() (err error) {
+	c := t.conn
+	if c.conn == nil {
+		return driver.ErrBadConn
+	}
+	defer c.endReadOnly()
+	if c.conn.AutocommitEnabled() {
+		// The transaction was already rolled back,
+		// for example by an interrupt.
+		return fmt.Errorf("sqlitedriver: commit: transaction already rolled back")
+	}
+	err = sqlitex.ExecuteTransient(c.conn, fmt.Sprintf("RELEASE %q;", savepointName), nil)
+	if err == nil {
+		return nil
+	}
+	if rollbackErr := t.rollback(); rollbackErr != nil {
+		return fmt.Errorf("sqlitedriver: commit: %w (rollback: %v)", err, rollbackErr)
+	}
+	return fmt.Errorf("sqlitedriver: commit: %w", err)
+}
+
+func (t *tx) Rollback() error {
+	c := t.conn
+	if c.conn == nil {
+		return driver.ErrBadConn
+	}
+	defer c.endReadOnly()
+	if err := t.rollback(); err != nil {
+		return fmt.Errorf("sqlitedriver: rollback: %w", err)
+	}
+	return nil
+}
+
+func (t *tx) rollback() error {
+	c := t.conn.conn
+	if c.AutocommitEnabled() {
+		// Nothing to roll back.
+		return nil
+	}
+	if err := sqlitex.ExecuteTransient(c, fmt.Sprintf("ROLLBACK TO %q;", savepointName), nil); err != nil {
+		return err
+	}
+	return sqlitex.ExecuteTransient(c, fmt.Sprintf("RELEASE %q;", savepointName), nil)
+}
+
+type result struct {
+	lastInsertID int64
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) { return r.lastInsertID, nil }
+func (r result) RowsAffected() (int64, error) { return r.rowsAffected, nil }
+
+func stepToEnd(stmt *sqlite.Stmt) error {
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			return err
+		}
+		if !hasRow {
+			return stmt.Reset()
+		}
+	}
+}
+
+func valuesToNamed(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, v := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: v}
+	}
+	return named
+}
@@ -0,0 +1,366 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitedriver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestConnector(t *testing.T) {
+	ctx := context.Background()
+	db := sql.OpenDB(NewConnector(filepath.Join(t.TempDir(), "test.db"), 0))
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	_, err := db.ExecContext(ctx, `
+		CREATE TABLE foo (id INTEGER PRIMARY KEY, name TEXT, data BLOB, score REAL);
+		INSERT INTO foo (name, data, score) VALUES ('alice', x'0102', 1.5);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := db.ExecContext(ctx, "INSERT INTO foo (name, data, score) VALUES (?, ?, ?);", "bob", nil, 2.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id, err := res.LastInsertId(); err != nil || id != 2 {
+		t.Errorf("LastInsertId() = %d, %v; want 2, <nil>", id, err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n != 1 {
+		t.Errorf("RowsAffected() = %d, %v; want 1, <nil>", n, err)
+	}
+
+	type row struct {
+		ID    int64
+		Name  string
+		Data  []byte
+		Score float64
+	}
+	var got []row
+	rows, err := db.QueryContext(ctx, "SELECT id, name, data, score FROM foo WHERE score > :min ORDER BY id;", sql.Named("min", 1.0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(&r.ID, &r.Name, &r.Data, &r.Score); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []row{
+		{ID: 1, Name: "alice", Data: []byte{1, 2}, Score: 1.5},
+		{ID: 2, Name: "bob", Score: 2.5},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("rows (-want +got):\n%s", diff)
+	}
+
+	t.Run("Nested", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		const query = "SELECT id FROM foo ORDER BY id;"
+		outer, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer outer.Close()
+		n := 0
+		for outer.Next() {
+			var count int
+			if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM ("+query[:len(query)-1]+");").Scan(&count); err != nil {
+				t.Fatal(err)
+			}
+			inner, err := conn.QueryContext(ctx, query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for inner.Next() {
+				n++
+			}
+			if err := inner.Err(); err != nil {
+				t.Fatal(err)
+			}
+			inner.Close()
+		}
+		if err := outer.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if n != 4 {
+			t.Errorf("inner rows = %d; want 4", n)
+		}
+	})
+
+	t.Run("Interleaved", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		const query = "SELECT id FROM foo ORDER BY id;"
+		outerCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		outer, err := conn.QueryContext(outerCtx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		inner, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer inner.Close()
+		if !outer.Next() {
+			t.Fatalf("outer.Next() = false; err = %v", outer.Err())
+		}
+		if err := outer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// Canceling the closed rows' context must not interrupt the other rows.
+		cancel()
+		n := 0
+		for inner.Next() {
+			n++
+		}
+		if err := inner.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if n != 2 {
+			t.Errorf("inner rows = %d; want 2", n)
+		}
+	})
+
+	t.Run("StmtCache", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		err = conn.Raw(func(driverConn any) error {
+			driverConn.(*Conn).SQLiteConn().SetStmtCacheSize(2)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		stmt, err := conn.PrepareContext(ctx, "SELECT 1;")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		// Statements held by database/sql must survive
+		// more queries than the connection's statement cache holds.
+		for i := 0; i < 5; i++ {
+			var got int
+			if err := conn.QueryRowContext(ctx, fmt.Sprintf("SELECT %d;", i+10)).Scan(&got); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var got int
+		if err := stmt.QueryRowContext(ctx).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != 1 {
+			t.Errorf("SELECT 1 = %d; want 1", got)
+		}
+	})
+
+	t.Run("CacheReuse", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		cacheStats := func() (stats sqlite.StmtCacheStats) {
+			err := conn.Raw(func(driverConn any) error {
+				stats = driverConn.(*Conn).SQLiteConn().StmtCacheStats()
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return stats
+		}
+		const query = "SELECT name FROM foo WHERE id = ?;"
+		before := cacheStats()
+		for i := 0; i < 3; i++ {
+			var name string
+			if err := conn.QueryRowContext(ctx, query, 1).Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+		}
+		after := cacheStats()
+		if got, want := after.Misses-before.Misses, int64(1); got != want {
+			t.Errorf("statement cache misses = %d; want %d", got, want)
+		}
+		if got, want := after.Hits-before.Hits, int64(2); got < want {
+			t.Errorf("statement cache hits = %d; want >=%d", got, want)
+		}
+	})
+
+	t.Run("InterruptRunning", func(t *testing.T) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		const query = "SELECT id FROM foo ORDER BY id;"
+		open, err := conn.QueryContext(ctx, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer open.Close()
+		if !open.Next() {
+			t.Fatalf("open.Next() = false; err = %v", open.Err())
+		}
+
+		// Canceling a running statement interrupts the connection.
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		var n int
+		err = conn.QueryRowContext(timeoutCtx, `
+			WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x+1 FROM c)
+			SELECT count(*) FROM c;
+		`).Scan(&n)
+		if got, want := sqlite.ErrCode(err), sqlite.ResultInterrupt; got != want {
+			t.Errorf("infinite query error = %v; want code %v", err, want)
+		}
+
+		// The rows that were open on the cached statement
+		// report the interrupt instead of starting over.
+		if open.Next() {
+			t.Error("open.Next() = true after interrupt")
+		}
+		if got, want := sqlite.ErrCode(open.Err()), sqlite.ResultInterrupt; got != want {
+			t.Errorf("open.Err() = %v; want code %v", open.Err(), want)
+		}
+		open.Close()
+
+		// The connection is usable afterward.
+		if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM foo;").Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Tx", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO foo (name) VALUES (?);", "carol"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		if err := db.QueryRowContext(ctx, "SELECT count(*) FROM foo;").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Errorf("count(*) after rollback = %d; want 2", count)
+		}
+
+		tx, err = db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO foo (name) VALUES (?);", "carol"); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.QueryRowContext(ctx, "SELECT count(*) FROM foo;").Scan(&count); err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("count(*) after commit = %d; want 3", count)
+		}
+	})
+
+	t.Run("ReadOnlyTx", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer tx.Rollback()
+		_, err = tx.ExecContext(ctx, "INSERT INTO foo (name) VALUES (?);", "dave")
+		if got, want := sqlite.ErrCode(err), sqlite.ResultReadOnly; got != want {
+			t.Errorf("INSERT error code = %v; want %v", got, want)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		_, err := db.ExecContext(ctx, "INSERT INTO foo (name) VALUES (?);", "erin")
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ExecContext(...) = _, %v; want %v", err, context.Canceled)
+		}
+	})
+}
+
+func TestPoolConnector(t *testing.T) {
+	ctx := context.Background()
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "test.db"), sqlitex.PoolOptions{PoolSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	db := sql.OpenDB(NewPoolConnector(pool))
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	if _, err := db.ExecContext(ctx, "CREATE TABLE foo (x INTEGER);"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Transactions from database/sql nest inside sqlite package transactions.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	err = conn.Raw(func(driverConn any) (err error) {
+		c := driverConn.(*Conn).SQLiteConn()
+		defer sqlitex.Save(c)(&err)
+		return sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (1);", nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sum int
+	if err := conn.QueryRowContext(ctx, "SELECT sum(x) FROM foo;").Scan(&sum); err != nil {
+		t.Fatal(err)
+	}
+	if sum != 1 {
+		t.Errorf("sum(x) = %d; want 1", sum)
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+// Package sqlitevfs provides ready-made implementations of [sqlite.VFS].
+//
+// The VFSes in this package serve read-only databases
+// directly from an [fs.FS] (such as an [embed.FS]) or an [io.ReaderAt]
+// without copying them to disk or into memory first.
+// Register one with [sqlite.RegisterVFS]
+// and then select it with the "vfs" URI parameter:
+//
+//	//go:embed testdata/ref.db
+//	var refFS embed.FS
+//
+//	func openRef() (*sqlite.Conn, error) {
+//		if err := sqlite.RegisterVFS("ref", sqlitevfs.NewFS(refFS), false); err != nil {
+//			return nil, err
+//		}
+//		return sqlite.OpenConn("file:testdata/ref.db?vfs=ref", sqlite.OpenReadOnly|sqlite.OpenURI)
+//	}
+//
+// The underlying data must not change while connections are open:
+// files are reported to SQLite as immutable,
+// so SQLite skips locking and change detection.
+// Temporary files that SQLite needs (e.g. for TEMP tables or large sorts)
+// are kept in memory.
+//
+// [embed.FS]: https://pkg.go.dev/embed#FS
+package sqlitevfs
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// sectorSize is the sector size reported for files.
+// It matches SQLite's default.
+const sectorSize = 4096
+
+// NewFS returns a read-only VFS that opens database files from fsys.
+// Database names are interpreted as slash-separated paths in fsys.
+// A leading slash is ignored.
+//
+// Files opened from fsys must implement [io.ReaderAt] or [io.Seeker].
+// Files from [embed.FS] and [os.DirFS] implement both.
+//
+// [embed.FS]: https://pkg.go.dev/embed#FS
+// [os.DirFS]: https://pkg.go.dev/os#DirFS
+func NewFS(fsys fs.FS) sqlite.VFS {
+	return fsVFS{fsys}
+}
+
+type fsVFS struct {
+	fsys fs.FS
+}
+
+func (vfs fsVFS) Open(name string, flags sqlite.OpenFlags) (sqlite.VFSFile, sqlite.OpenFlags, error) {
+	if name == "" || flags&sqlite.OpenDeleteOnClose != 0 {
+		return new(tempFile), flags, nil
+	}
+	if flags&sqlite.OpenMainDB == 0 {
+		return nil, 0, fmt.Errorf("sqlitevfs: open %s: %w", name, fs.ErrPermission)
+	}
+	name = cleanFSPath(name)
+	f, err := vfs.fsys.Open(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, err
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, 0, fmt.Errorf("sqlitevfs: open %s: is a directory", name)
+	}
+	var r io.ReaderAt
+	switch f := f.(type) {
+	case io.ReaderAt:
+		r = f
+	case io.ReadSeeker:
+		r = &seekReaderAt{f}
+	default:
+		f.Close()
+		return nil, 0, fmt.Errorf("sqlitevfs: open %s: file does not support random access", name)
+	}
+	return &readOnlyFile{r: r, size: info.Size(), closer: f}, readOnlyFlags(flags), nil
+}
+
+func (vfs fsVFS) Delete(name string, syncDir bool) error {
+	if _, err := fs.Stat(vfs.fsys, cleanFSPath(name)); err != nil {
+		return err
+	}
+	return fmt.Errorf("sqlitevfs: delete %s: %w", name, fs.ErrPermission)
+}
+
+func (vfs fsVFS) Access(name string, flag sqlite.AccessFlag) (bool, error) {
+	if flag == sqlite.AccessReadWrite {
+		return false, nil
+	}
+	_, err := fs.Stat(vfs.fsys, cleanFSPath(name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (vfs fsVFS) FullPathname(name string) (string, error) {
+	return cleanFSPath(name), nil
+}
+
+// cleanFSPath converts a database name into an [fs.ValidPath].
+func cleanFSPath(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// NewReaderAt returns a read-only VFS that serves the database
+// stored in the first size bytes of r.
+// Every database opened with the VFS refers to the same data,
+// regardless of name.
+func NewReaderAt(r io.ReaderAt, size int64) sqlite.VFS {
+	return readerAtVFS{r, size}
+}
+
+type readerAtVFS struct {
+	r    io.ReaderAt
+	size int64
+}
+
+func (vfs readerAtVFS) Open(name string, flags sqlite.OpenFlags) (sqlite.VFSFile, sqlite.OpenFlags, error) {
+	if name == "" || flags&sqlite.OpenDeleteOnClose != 0 {
+		return new(tempFile), flags, nil
+	}
+	if flags&sqlite.OpenMainDB == 0 {
+		return nil, 0, fmt.Errorf("sqlitevfs: open %s: %w", name, fs.ErrPermission)
+	}
+	return &readOnlyFile{r: vfs.r, size: vfs.size}, readOnlyFlags(flags), nil
+}
+
+func (vfs readerAtVFS) Delete(name string, syncDir bool) error {
+	return fmt.Errorf("sqlitevfs: delete %s: %w", name, fs.ErrPermission)
+}
+
+func (vfs readerAtVFS) Access(name string, flag sqlite.AccessFlag) (bool, error) {
+	// The only file is the database itself,
+	// which SQLite never checks for with Access.
+	return false, nil
+}
+
+func (vfs readerAtVFS) FullPathname(name string) (string, error) {
+	return name, nil
+}
+
+func readOnlyFlags(flags sqlite.OpenFlags) sqlite.OpenFlags {
+	return flags&^(sqlite.OpenReadWrite|sqlite.OpenCreate) | sqlite.OpenReadOnly
+}
+
+// readOnlyFile is an immutable [sqlite.VFSFile].
+type readOnlyFile struct {
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+}
+
+func (f *readOnlyFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= f.size {
+		return 0, io.EOF
+	}
+	short := false
+	if remaining := f.size - off; int64(len(p)) > remaining {
+		p = p[:remaining]
+		short = true
+	}
+	n, err := f.r.ReadAt(p, off)
+	if n == len(p) && short {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (f *readOnlyFile) WriteAt(p []byte, off int64) (int, error) {
+	return 0, sqlite.ResultReadOnly.ToError()
+}
+
+func (f *readOnlyFile) Truncate(size int64) error {
+	return sqlite.ResultReadOnly.ToError()
+}
+
+func (f *readOnlyFile) Sync(flags sqlite.SyncFlag) error {
+	return nil
+}
+
+func (f *readOnlyFile) FileSize() (int64, error) {
+	return f.size, nil
+}
+
+func (f *readOnlyFile) Lock(level sqlite.LockLevel) error {
+	return nil
+}
+
+func (f *readOnlyFile) Unlock(level sqlite.LockLevel) error {
+	return nil
+}
+
+func (f *readOnlyFile) CheckReservedLock() (bool, error) {
+	return false, nil
+}
+
+func (f *readOnlyFile) SectorSize() int {
+	return sectorSize
+}
+
+func (f *readOnlyFile) DeviceCharacteristics() sqlite.DeviceCharacteristics {
+	return sqlite.IOCapImmutable
+}
+
+func (f *readOnlyFile) Close() error {
+	if f.closer == nil {
+		return nil
+	}
+	return f.closer.Close()
+}
+
+// seekReaderAt adapts an [io.ReadSeeker] to an [io.ReaderAt].
+// SQLite serializes calls to a single file,
+// so no locking is required.
+type seekReaderAt struct {
+	r io.ReadSeeker
+}
+
+func (sr *seekReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := sr.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(sr.r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// tempFile is an in-memory [sqlite.VFSFile]
+// used for temporary files that are deleted on close.
+type tempFile struct {
+	data []byte
+}
+
+func (f *tempFile) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *tempFile) WriteAt(p []byte, off int64) (int, error) {
+	if end := off + int64(len(p)); end > int64(len(f.data)) {
+		f.data = append(f.data, make([]byte, end-int64(len(f.data)))...)
+	}
+	return copy(f.data[off:], p), nil
+}
+
+func (f *tempFile) Truncate(size int64) error {
+	if size < int64(len(f.data)) {
+		f.data = f.data[:size]
+	}
+	return nil
+}
+
+func (f *tempFile) Sync(flags sqlite.SyncFlag) error {
+	return nil
+}
+
+func (f *tempFile) FileSize() (int64, error) {
+	return int64(len(f.data)), nil
+}
+
+func (f *tempFile) Lock(level sqlite.LockLevel) error {
+	return nil
+}
+
+func (f *tempFile) Unlock(level sqlite.LockLevel) error {
+	return nil
+}
+
+func (f *tempFile) CheckReservedLock() (bool, error) {
+	return false, nil
+}
+
+func (f *tempFile) SectorSize() int {
+	return sectorSize
+}
+
+func (f *tempFile) DeviceCharacteristics() sqlite.DeviceCharacteristics {
+	return sqlite.IOCapAtomic | sqlite.IOCapSafeAppend | sqlite.IOCapSequential | sqlite.IOCapPowersafeOverwrite
+}
+
+func (f *tempFile) Close() error {
+	f.data = nil
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitevfs
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestFS(t *testing.T) {
+	db := newTestDatabase(t)
+	fsys := fstest.MapFS{
+		"data/ref.db": &fstest.MapFile{Data: db},
+	}
+	if err := sqlite.RegisterVFS("sqlitevfs-test-fs", NewFS(fsys), false); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sqlite.UnregisterVFS("sqlitevfs-test-fs"); err != nil {
+			t.Error(err)
+		}
+	}()
+	testReadOnlyDatabase(t, "file:/data/ref.db?vfs=sqlitevfs-test-fs")
+
+	t.Run("NotExist", func(t *testing.T) {
+		c, err := sqlite.OpenConn("file:missing.db?vfs=sqlitevfs-test-fs", sqlite.OpenReadOnly|sqlite.OpenURI)
+		if err == nil {
+			c.Close()
+			t.Fatal("OpenConn did not return an error")
+		}
+		if got, want := sqlite.ErrCode(err), sqlite.ResultCantOpen; got != want {
+			t.Errorf("OpenConn error code = %v; want %v", got, want)
+		}
+	})
+}
+
+func TestReaderAt(t *testing.T) {
+	db := newTestDatabase(t)
+	vfs := NewReaderAt(bytes.NewReader(db), int64(len(db)))
+	if err := sqlite.RegisterVFS("sqlitevfs-test-readerat", vfs, false); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sqlite.UnregisterVFS("sqlitevfs-test-readerat"); err != nil {
+			t.Error(err)
+		}
+	}()
+	testReadOnlyDatabase(t, "file:ref.db?vfs=sqlitevfs-test-readerat")
+}
+
+func newTestDatabase(tb testing.TB) []byte {
+	tb.Helper()
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			tb.Error(err)
+		}
+	}()
+	err = sqlitex.ExecuteScript(c, `
+		CREATE TABLE foo (x INTEGER);
+		WITH RECURSIVE series(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM series WHERE x < 1000)
+		INSERT INTO foo (x) SELECT x FROM series;
+	`, nil)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	db, err := c.Serialize("main")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return db
+}
+
+func testReadOnlyDatabase(t *testing.T, uri string) {
+	t.Helper()
+	c, err := sqlite.OpenConn(uri, sqlite.OpenReadOnly|sqlite.OpenURI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	sum, err := sqlitex.ResultInt64(c.Prep("SELECT sum(x) FROM foo;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(1000 * 1001 / 2); sum != want {
+		t.Errorf("sum(x) = %d; want %d", sum, want)
+	}
+
+	// TEMP tables are stored in memory.
+	err = sqlitex.ExecuteScript(c, `
+		CREATE TEMP TABLE bar AS SELECT x FROM foo WHERE x % 2 = 0;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := sqlitex.ResultInt(c.Prep("SELECT count(*) FROM temp.bar;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 500 {
+		t.Errorf("count(*) = %d; want 500", n)
+	}
+
+	err = sqlitex.ExecuteTransient(c, "INSERT INTO foo (x) VALUES (1001);", nil)
+	if got, want := sqlite.ErrCode(err), sqlite.ResultReadOnly; got != want {
+		t.Errorf("INSERT error code = %v; want %v", got, want)
+	}
+}
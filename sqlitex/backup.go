@@ -0,0 +1,185 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// BackupOptions is the set of optional arguments for [BackupTo] and [BackupFrom].
+type BackupOptions struct {
+	// DatabaseName is the name of the database on the given connection
+	// to copy to or from:
+	// "main" or "" for the main database,
+	// "temp" for the temporary database,
+	// or the name of an attached database.
+	DatabaseName string
+
+	// PagesPerStep is the number of pages copied in each step.
+	// Between steps, the source database is unlocked
+	// so that other connections can use it,
+	// and the backup stops if the context is done.
+	// If PagesPerStep is zero or negative,
+	// the entire database is copied in a single step,
+	// unless the context can be canceled,
+	// in which case 100 pages are copied in each step.
+	PagesPerStep int
+	// Sleep is the duration to wait between steps.
+	Sleep time.Duration
+
+	// Progress is called after each successful step
+	// with the number of pages still to be copied
+	// and the total number of pages in the source database.
+	Progress func(remaining, pageCount int)
+
+	// MaxRetries is the maximum number of consecutive times
+	// a step that fails with [sqlite.ResultBusy] or [sqlite.ResultLocked]
+	// is retried before the error is returned.
+	// It is interpreted the same way as [TxOptions.MaxRetries].
+	MaxRetries int
+	// RetryDelay is the duration to wait before retrying a step
+	// that failed with [sqlite.ResultBusy] or [sqlite.ResultLocked].
+	// If RetryDelay is zero, then 10 milliseconds is used.
+	RetryDelay time.Duration
+}
+
+// BackupTo copies a database on src to a new or existing database file at dstPath
+// using the [online backup API].
+// Any existing content in the destination database is replaced.
+// The copy is a consistent snapshot of the source database
+// even if other connections write to it during the backup,
+// in which case the backup restarts.
+// opts may be nil to copy the main database with the default options.
+//
+// If ctx is done before the backup completes, BackupTo returns ctx.Err()
+// and the destination database is left unchanged.
+//
+// [online backup API]: https://sqlite.org/backup.html
+func BackupTo(ctx context.Context, src *sqlite.Conn, dstPath string, opts *BackupOptions) (err error) {
+	if opts == nil {
+		opts = new(BackupOptions)
+	}
+	dst, err := sqlite.OpenConn(dstPath, sqlite.OpenReadWrite|sqlite.OpenCreate|sqlite.OpenURI)
+	if err != nil {
+		return fmt.Errorf("sqlitex: backup to %s: %w", dstPath, err)
+	}
+	defer func() {
+		if closeErr := dst.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("sqlitex: backup to %s: %w", dstPath, closeErr)
+		}
+	}()
+	if err := backup(ctx, dst, "main", src, opts.DatabaseName, opts); err != nil {
+		return fmt.Errorf("sqlitex: backup to %s: %w", dstPath, err)
+	}
+	return nil
+}
+
+// BackupFrom replaces a database on dst with the contents
+// of the database file at srcPath
+// using the [online backup API].
+// opts may be nil to restore to the main database with the default options.
+//
+// If ctx is done before the backup completes, BackupFrom returns ctx.Err()
+// and the destination database is left unchanged.
+//
+// [online backup API]: https://sqlite.org/backup.html
+func BackupFrom(ctx context.Context, dst *sqlite.Conn, srcPath string, opts *BackupOptions) (err error) {
+	if opts == nil {
+		opts = new(BackupOptions)
+	}
+	src, err := sqlite.OpenConn(srcPath, sqlite.OpenReadOnly|sqlite.OpenURI)
+	if err != nil {
+		return fmt.Errorf("sqlitex: backup from %s: %w", srcPath, err)
+	}
+	defer func() {
+		if closeErr := src.Close(); err == nil && closeErr != nil {
+			err = fmt.Errorf("sqlitex: backup from %s: %w", srcPath, closeErr)
+		}
+	}()
+	if err := backup(ctx, dst, opts.DatabaseName, src, "main", opts); err != nil {
+		return fmt.Errorf("sqlitex: backup from %s: %w", srcPath, err)
+	}
+	return nil
+}
+
+// defaultPagesPerStep is the number of pages copied in each step
+// when [BackupOptions.PagesPerStep] is not positive
+// and the context can be canceled.
+const defaultPagesPerStep = 100
+
+func backup(ctx context.Context, dst *sqlite.Conn, dstName string, src *sqlite.Conn, srcName string, opts *BackupOptions) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b, err := sqlite.NewBackup(dst, dstName, src, srcName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := b.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	n := opts.PagesPerStep
+	if n <= 0 {
+		// A single step can't be interrupted,
+		// so copy in smaller steps if the backup may be canceled.
+		if ctx.Done() != nil {
+			n = defaultPagesPerStep
+		} else {
+			n = -1
+		}
+	}
+	retryDelay := opts.RetryDelay
+	if retryDelay == 0 {
+		retryDelay = 10 * time.Millisecond
+	}
+	limit := maxRetries(opts.MaxRetries)
+	retries := 0
+	for {
+		more, err := b.Step(n)
+		if err != nil {
+			if !more || retries >= limit {
+				return err
+			}
+			retries++
+			if err := sleep(ctx, retryDelay); err != nil {
+				return err
+			}
+			continue
+		}
+		retries = 0
+		if opts.Progress != nil {
+			opts.Progress(b.Remaining(), b.PageCount())
+		}
+		if !more {
+			return nil
+		}
+		if opts.Sleep > 0 {
+			if err := sleep(ctx, opts.Sleep); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for the given duration or until ctx is done,
+// whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+	src, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = ExecuteScript(src, `
+		CREATE TABLE foo (x BLOB);
+		WITH RECURSIVE series(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM series WHERE x < 100)
+		INSERT INTO foo (x) SELECT randomblob(1024) FROM series;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "backup.db")
+	steps := 0
+	lastRemaining := -1
+	err = BackupTo(ctx, src, dstPath, &BackupOptions{
+		PagesPerStep: 10,
+		Progress: func(remaining, pageCount int) {
+			steps++
+			lastRemaining = remaining
+			if pageCount <= 0 {
+				t.Errorf("Progress pageCount = %d; want >0", pageCount)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if steps < 2 {
+		t.Errorf("Progress called %d times; want >=2", steps)
+	}
+	if lastRemaining != 0 {
+		t.Errorf("final remaining = %d; want 0", lastRemaining)
+	}
+
+	restored, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := restored.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := BackupFrom(ctx, restored, dstPath, nil); err != nil {
+		t.Fatal(err)
+	}
+	n, err := ResultInt(restored.Prep("SELECT count(*) FROM foo;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 100 {
+		t.Errorf("count(*) = %d; want 100", n)
+	}
+}
+
+func TestBackupDefaultStep(t *testing.T) {
+	src, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = ExecuteScript(src, `
+		CREATE TABLE foo (x BLOB);
+		WITH RECURSIVE series(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM series WHERE x < 100)
+		INSERT INTO foo (x) SELECT randomblob(4096) FROM series;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		multiple bool
+	}{
+		{"Background", context.Background(), false},
+		{"Cancelable", cancelCtx, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			steps := 0
+			err := BackupTo(test.ctx, src, filepath.Join(t.TempDir(), "backup.db"), &BackupOptions{
+				Progress: func(remaining, pageCount int) {
+					steps++
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := steps > 1; got != test.multiple {
+				t.Errorf("Progress called %d times; want multiple = %t", steps, test.multiple)
+			}
+		})
+	}
+}
+
+func TestBackupCanceled(t *testing.T) {
+	src, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = ExecuteScript(src, `
+		CREATE TABLE foo (x BLOB);
+		WITH RECURSIVE series(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM series WHERE x < 100)
+		INSERT INTO foo (x) SELECT randomblob(1024) FROM series;
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dstPath := filepath.Join(t.TempDir(), "backup.db")
+	err = BackupTo(ctx, src, dstPath, &BackupOptions{
+		PagesPerStep: 1,
+		Progress: func(remaining, pageCount int) {
+			cancel()
+		},
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("BackupTo(...) = %v; want %v", err, context.Canceled)
+	}
+
+	dst, err := sqlite.OpenConn(dstPath, sqlite.OpenReadOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := dst.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	n, err := ResultInt(dst.Prep("SELECT count(*) FROM sqlite_schema;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("destination has %d schema entries; want 0", n)
+	}
+}
+
+func TestBackupBusy(t *testing.T) {
+	dir := t.TempDir()
+	srcPath := filepath.Join(dir, "src.db")
+	src, err := sqlite.OpenConn(srcPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ExecuteTransient(src, "CREATE TABLE foo (x INTEGER);", nil)
+	if closeErr := src.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dstPath := filepath.Join(dir, "dst.db")
+	dst, err := sqlite.OpenConn(dstPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := dst.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	// Fail with SQLITE_BUSY instead of waiting for the lock.
+	dst.SetBusyTimeout(0)
+	blocker, err := sqlite.OpenConn(dstPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := blocker.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := ExecuteTransient(blocker, "BEGIN EXCLUSIVE;", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer ExecuteTransient(blocker, "ROLLBACK;", nil)
+
+	// With the default MaxRetries, the backup gives up
+	// instead of retrying until the context is done.
+	err = BackupFrom(context.Background(), dst, srcPath, &BackupOptions{
+		RetryDelay: time.Microsecond,
+	})
+	if got, want := sqlite.ErrCode(err).ToPrimary(), sqlite.ResultBusy; got != want {
+		t.Errorf("BackupFrom(...) = %v; want code %v", err, want)
+	}
+}
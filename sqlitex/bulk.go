@@ -0,0 +1,227 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"strings"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// BulkOptions is the set of optional arguments for [BulkInsert].
+type BulkOptions struct {
+	// RowsPerStatement is the number of rows inserted by each INSERT statement.
+	// If RowsPerStatement is zero, then 100 is used.
+	// It is lowered if the statement would have more parameters
+	// than the connection's [sqlite.LimitVariableNumber].
+	RowsPerStatement int
+	// CommitEvery is the number of rows inserted in each transaction.
+	// If CommitEvery is zero, then 10,000 is used.
+	// If CommitEvery is negative, then all rows are inserted in one transaction.
+	CommitEvery int
+	// OnConflict is an optional [upsert clause] appended to each INSERT statement,
+	// like "ON CONFLICT (id) DO UPDATE SET name = excluded.name".
+	//
+	// [upsert clause]: https://www.sqlite.org/lang_upsert.html
+	OnConflict string
+}
+
+// BulkInsert inserts rows into the given columns of table.
+// Each row must have one value per column,
+// which is converted the same way as [ExecOptions.Args].
+//
+// Rows are inserted with multi-row INSERT statements,
+// and the statement for a full batch of rows is cached on the connection
+// as if by [sqlite.Conn.Prepare].
+// Every [BulkOptions.CommitEvery] rows are inserted in a savepoint
+// (see [Save]) that is released before the next rows are read,
+// so outside a transaction, each batch is committed.
+// If an error occurs, the current batch is rolled back
+// and the rows committed before it are kept.
+//
+// BulkInsert returns the number of rows written by committed batches,
+// as counted by [sqlite.Conn.Changes].
+// Rows that were skipped because of the OnConflict clause are not counted.
+func BulkInsert(conn *sqlite.Conn, table string, columns []string, rows iter.Seq[[]any], opts BulkOptions) (n int64, err error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("sqlitex: bulk insert into %s: no columns", table)
+	}
+	maxRows := int(conn.Limit(sqlite.LimitVariableNumber, -1)) / len(columns)
+	if maxRows == 0 {
+		return 0, fmt.Errorf("sqlitex: bulk insert into %s: too many columns (%d)", table, len(columns))
+	}
+	b := &bulkInserter{
+		conn:        conn,
+		table:       table,
+		columns:     columns,
+		onConflict:  opts.OnConflict,
+		perStmt:     opts.RowsPerStatement,
+		commitEvery: opts.CommitEvery,
+	}
+	if b.perStmt <= 0 {
+		b.perStmt = 100
+	}
+	b.perStmt = min(b.perStmt, maxRows)
+	if b.commitEvery == 0 {
+		b.commitEvery = 10_000
+	}
+	if b.commitEvery > 0 {
+		b.perStmt = min(b.perStmt, b.commitEvery)
+	}
+	b.args = make([]any, 0, b.perStmt*len(columns))
+
+	next, stop := iter.Pull(rows)
+	defer stop()
+	for {
+		written, more, err := b.insertBatch(next)
+		n += written
+		if err != nil {
+			return n, fmt.Errorf("sqlitex: bulk insert into %s: %w", table, err)
+		}
+		if !more {
+			return n, nil
+		}
+	}
+}
+
+type bulkInserter struct {
+	conn        *sqlite.Conn
+	table       string
+	columns     []string
+	onConflict  string
+	perStmt     int
+	commitEvery int
+
+	args []any // arguments for rows not yet inserted
+	row  int   // number of rows read
+}
+
+// insertBatch inserts the next CommitEvery rows from next in a savepoint.
+// It reports the number of rows written
+// and whether there may be more rows to insert.
+func (b *bulkInserter) insertBatch(next func() ([]any, bool)) (written int64, more bool, err error) {
+	row, ok := next()
+	if !ok {
+		return 0, false, nil
+	}
+	releaseFn, err := savepoint(b.conn, "sqlitex.BulkInsert")
+	if err != nil {
+		return 0, false, err
+	}
+	defer func() {
+		if err != nil {
+			written = 0
+		}
+	}()
+	defer releaseFn(&err)
+
+	for n := 1; ; n++ {
+		b.row++
+		if len(row) != len(b.columns) {
+			return written, false, fmt.Errorf("row %d has %d values, want %d", b.row, len(row), len(b.columns))
+		}
+		b.args = append(b.args, row...)
+		if len(b.args) == cap(b.args) {
+			changes, err := b.flush()
+			if err != nil {
+				return written, false, err
+			}
+			written += changes
+		}
+		if n == b.commitEvery {
+			break
+		}
+		if row, ok = next(); !ok {
+			break
+		}
+	}
+	changes, err := b.flush()
+	if err != nil {
+		return written, false, err
+	}
+	written += changes
+	return written, ok, nil
+}
+
+// flush inserts the rows in b.args.
+func (b *bulkInserter) flush() (changes int64, err error) {
+	nrows := len(b.args) / len(b.columns)
+	if nrows == 0 {
+		return 0, nil
+	}
+	query := b.query(nrows)
+	var stmt *sqlite.Stmt
+	if nrows == b.perStmt {
+		stmt, err = b.conn.Prepare(query)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Reset()
+	} else {
+		stmt, _, err = b.conn.PrepareTransient(query)
+		if err != nil {
+			return 0, err
+		}
+		defer stmt.Finalize()
+	}
+	for i, arg := range b.args {
+		if err := setArg(stmt, i+1, forbidUnknownTypes, reflect.ValueOf(arg)); err != nil {
+			return 0, err
+		}
+	}
+	clear(b.args)
+	b.args = b.args[:0]
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			return 0, err
+		}
+		if !hasRow {
+			break
+		}
+	}
+	return int64(b.conn.Changes()), nil
+}
+
+// query returns an INSERT statement for nrows rows.
+func (b *bulkInserter) query(nrows int) string {
+	sb := new(strings.Builder)
+	sb.WriteString("INSERT INTO ")
+	sb.WriteString(quoteIdent(b.table))
+	sb.WriteString(" (")
+	for i, col := range b.columns {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(quoteIdent(col))
+	}
+	sb.WriteString(") VALUES ")
+	for i := 0; i < nrows; i++ {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString("(")
+		for j := range b.columns {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString("?")
+		}
+		sb.WriteString(")")
+	}
+	if b.onConflict != "" {
+		sb.WriteString(" ")
+		sb.WriteString(b.onConflict)
+	}
+	sb.WriteString(";")
+	return sb.String()
+}
+
+// quoteIdent returns name as a quoted SQL identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
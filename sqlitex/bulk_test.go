@@ -0,0 +1,110 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex_test
+
+import (
+	"fmt"
+	"iter"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestBulkInsert(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(conn, `CREATE TABLE "my table" (id INTEGER PRIMARY KEY, name TEXT NOT NULL);`, nil); err != nil {
+		t.Fatal(err)
+	}
+	genRows := func(start, end int, suffix string) iter.Seq[[]any] {
+		return func(yield func([]any) bool) {
+			for i := start; i < end; i++ {
+				if !yield([]any{i, fmt.Sprintf("row %d%s", i, suffix)}) {
+					return
+				}
+			}
+		}
+	}
+	count := func(t *testing.T, query string) int {
+		t.Helper()
+		n, err := sqlitex.ResultInt(conn.Prep(query))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	columns := []string{"id", "name"}
+
+	t.Run("Insert", func(t *testing.T) {
+		commits := 0
+		conn.SetCommitHook(func() bool {
+			commits++
+			return true
+		})
+		defer conn.SetCommitHook(nil)
+		n, err := sqlitex.BulkInsert(conn, "my table", columns, genRows(0, 250, ""), sqlitex.BulkOptions{
+			RowsPerStatement: 7,
+			CommitEvery:      100,
+		})
+		if n != 250 || err != nil {
+			t.Errorf("BulkInsert(...) = %d, %v; want 250, <nil>", n, err)
+		}
+		if commits != 3 {
+			t.Errorf("%d commits; want 3", commits)
+		}
+		if got := count(t, `SELECT count(*) FROM "my table";`); got != 250 {
+			t.Errorf("count(*) = %d; want 250", got)
+		}
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		n, err := sqlitex.BulkInsert(conn, "my table", columns, genRows(200, 300, " updated"), sqlitex.BulkOptions{
+			OnConflict: "ON CONFLICT (id) DO UPDATE SET name = excluded.name",
+		})
+		if n != 100 || err != nil {
+			t.Errorf("BulkInsert(...) = %d, %v; want 100, <nil>", n, err)
+		}
+		if got := count(t, `SELECT count(*) FROM "my table";`); got != 300 {
+			t.Errorf("count(*) = %d; want 300", got)
+		}
+		if got := count(t, `SELECT count(*) FROM "my table" WHERE name LIKE '% updated';`); got != 100 {
+			t.Errorf("updated rows = %d; want 100", got)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		rows := func(yield func([]any) bool) {
+			for i := 1000; i < 1150; i++ {
+				row := []any{i, "x"}
+				if i == 1120 {
+					row = row[:1]
+				}
+				if !yield(row) {
+					return
+				}
+			}
+		}
+		n, err := sqlitex.BulkInsert(conn, "my table", columns, rows, sqlitex.BulkOptions{CommitEvery: 100})
+		if err == nil {
+			t.Error("BulkInsert(...) did not return an error")
+		}
+		if n != 100 {
+			t.Errorf("BulkInsert(...) = %d, _; want 100", n)
+		}
+		if got := count(t, `SELECT count(*) FROM "my table" WHERE id >= 1000;`); got != 100 {
+			t.Errorf("count(*) = %d; want 100", got)
+		}
+		if !conn.AutocommitEnabled() {
+			t.Error("connection left in a transaction")
+		}
+	})
+}
@@ -18,11 +18,13 @@
 package sqlitex
 
 import (
+	"encoding"
 	"fmt"
 	"io"
 	"io/fs"
 	"reflect"
 	"strings"
+	"time"
 
 	"zombiezen.com/go/sqlite"
 )
@@ -35,32 +37,39 @@ type ExecOptions struct {
 	//
 	// Basic reflection on Args is used to map:
 	//
-	//  - integers    to BindInt64
-	//  - floats      to BindFloat
-	//  - []byte      to BindBytes
-	//  - string      to BindText
-	//  - bool        to BindBool
-	//  - untyped nil to BindNull
+	//  - [sqlite.Binder] to its BindParam method
+	//  - integers        to BindInt64
+	//  - floats          to BindFloat
+	//  - []byte          to BindBytes
+	//  - string          to BindText
+	//  - bool            to BindBool
+	//  - [time.Time]     to BindTime
+	//  - untyped nil     to BindNull
+	//  - nil pointers    to BindNull
+	//  - other pointers  to the value they point to
+	//  - [encoding.TextMarshaler] to BindText with the result of MarshalText
 	//
-	// All other kinds are printed using [fmt.Sprint] and passed to BindText.
+	// Other types are an error.
+	// (The deprecated [Exec] and [ExecTransient] functions
+	// print other types using [fmt.Sprint] and pass them to BindText.)
 	Args []any
 
 	// Named is the set of named arguments to bind to the statement. Keys must
 	// start with ':', '@', or '$'. See https://sqlite.org/lang_expr.html for more
 	// details.
 	//
-	// Basic reflection on Named is used to map:
-	//
-	//  - integers    to BindInt64
-	//  - floats      to BindFloat
-	//  - []byte      to BindBytes
-	//  - string      to BindText
-	//  - bool        to BindBool
-	//  - untyped nil to BindNull
-	//
-	// All other kinds are printed using [fmt.Sprint] and passed to BindText.
+	// Values are converted the same way as Args.
 	Named map[string]any
 
+	// NamedStruct is a struct (or a pointer to a struct)
+	// whose fields are bound to named parameters
+	// that are not present in Named.
+	// A parameter like :name, @name, or $name is bound
+	// to the field that the [Query] rules would map a "name" column to.
+	// Fields that do not correspond to a parameter are ignored.
+	// Values are converted the same way as Args.
+	NamedStruct any
+
 	// ResultFunc is called for each result row.
 	// If ResultFunc returns an error then iteration ceases
 	// and the execution function returns the error value.
@@ -122,7 +131,7 @@ func Execute(conn *sqlite.Conn, query string, opts *ExecOptions) error {
 	if err != nil {
 		return err
 	}
-	err = exec(stmt, forbidMissing|forbidExtra, opts)
+	err = exec(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts)
 	resetErr := stmt.Reset()
 	if err == nil {
 		err = resetErr
@@ -151,7 +160,7 @@ func ExecuteFS(conn *sqlite.Conn, fsys fs.FS, filename string, opts *ExecOptions
 	if err != nil {
 		return fmt.Errorf("sqlitex: execute %s: %w", filename, err)
 	}
-	err = exec(stmt, forbidMissing|forbidExtra, opts)
+	err = exec(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts)
 	resetErr := stmt.Reset()
 	if err != nil {
 		// Don't strip the error query: we already do this inside exec.
@@ -210,7 +219,7 @@ func ExecuteTransient(conn *sqlite.Conn, query string, opts *ExecOptions) (err e
 	if trailingBytes != 0 {
 		return fmt.Errorf("sqlitex: execute: query %q has trailing bytes", query)
 	}
-	return exec(stmt, forbidMissing|forbidExtra, opts)
+	return exec(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts)
 }
 
 // ExecTransientFS is an alias for [ExecuteTransientFS].
@@ -233,7 +242,7 @@ func ExecuteTransientFS(conn *sqlite.Conn, fsys fs.FS, filename string, opts *Ex
 		return fmt.Errorf("sqlitex: execute %s: %w", filename, err)
 	}
 	defer stmt.Finalize()
-	err = exec(stmt, forbidMissing|forbidExtra, opts)
+	err = exec(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts)
 	resetErr := stmt.Reset()
 	if err != nil {
 		// Don't strip the error query: we already do this inside exec.
@@ -265,9 +274,32 @@ func PrepareTransientFS(conn *sqlite.Conn, fsys fs.FS, filename string) (*sqlite
 const (
 	forbidMissing = 1 << iota
 	forbidExtra
+	forbidUnknownTypes
 )
 
 func exec(stmt *sqlite.Stmt, flags uint8, opts *ExecOptions) (err error) {
+	if err := bindArgs(stmt, flags, opts); err != nil {
+		return err
+	}
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			return err
+		}
+		if !hasRow {
+			break
+		}
+		if opts != nil && opts.ResultFunc != nil {
+			if err := opts.ResultFunc(stmt); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// bindArgs binds the arguments in opts to stmt.
+func bindArgs(stmt *sqlite.Stmt, flags uint8, opts *ExecOptions) error {
 	paramCount := stmt.BindParamCount()
 	provided := newBitset(paramCount)
 	if opts != nil {
@@ -277,9 +309,11 @@ func exec(stmt *sqlite.Stmt, flags uint8, opts *ExecOptions) (err error) {
 		}
 		for i, arg := range opts.Args {
 			provided.set(i)
-			setArg(stmt, i+1, reflect.ValueOf(arg))
+			if err := setArg(stmt, i+1, flags, reflect.ValueOf(arg)); err != nil {
+				return err
+			}
 		}
-		if err := setNamed(stmt, provided, flags, opts.Named); err != nil {
+		if err := setNamed(stmt, provided, flags, opts.Named, opts.NamedStruct); err != nil {
 			return err
 		}
 	}
@@ -291,24 +325,20 @@ func exec(stmt *sqlite.Stmt, flags uint8, opts *ExecOptions) (err error) {
 		}
 		return fmt.Errorf("sqlitex: missing argument for %s", name)
 	}
-	for {
-		hasRow, err := stmt.Step()
-		if err != nil {
-			return err
-		}
-		if !hasRow {
-			break
-		}
-		if opts != nil && opts.ResultFunc != nil {
-			if err := opts.ResultFunc(stmt); err != nil {
-				return err
-			}
-		}
-	}
 	return nil
 }
 
-func setArg(stmt *sqlite.Stmt, i int, v reflect.Value) {
+func setArg(stmt *sqlite.Stmt, i int, flags uint8, v reflect.Value) error {
+	if b, ok := asInterface[sqlite.Binder](v); ok {
+		if err := b.BindParam(stmt, i); err != nil {
+			return fmt.Errorf("sqlitex: bind %s: %w", paramName(stmt, i), err)
+		}
+		return nil
+	}
+	if v.IsValid() && v.Type() == timeType {
+		stmt.BindTime(i, v.Interface().(time.Time))
+		return nil
+	}
 	switch v.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		stmt.BindInt64(i, v.Int())
@@ -322,19 +352,78 @@ func setArg(stmt *sqlite.Stmt, i int, v reflect.Value) {
 		stmt.BindBool(i, v.Bool())
 	case reflect.Invalid:
 		stmt.BindNull(i)
+	case reflect.Pointer:
+		if flags&forbidUnknownTypes == 0 {
+			stmt.BindText(i, fmt.Sprint(v.Interface()))
+		} else if v.IsNil() {
+			stmt.BindNull(i)
+		} else {
+			return setArg(stmt, i, flags, v.Elem())
+		}
 	default:
 		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
 			stmt.BindBytes(i, v.Bytes())
-		} else {
+		} else if flags&forbidUnknownTypes == 0 {
 			stmt.BindText(i, fmt.Sprint(v.Interface()))
+		} else if m, ok := asInterface[encoding.TextMarshaler](v); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return fmt.Errorf("sqlitex: bind %s: %w", paramName(stmt, i), err)
+			}
+			stmt.BindText(i, string(text))
+		} else {
+			return fmt.Errorf("sqlitex: bind %s: unsupported type %v", paramName(stmt, i), v.Type())
 		}
 	}
+	return nil
 }
 
-func setNamed(stmt *sqlite.Stmt, provided bitset, flags uint8, args map[string]any) error {
-	if len(args) == 0 {
+// asInterface returns v as the interface type T
+// if either v or a pointer to v implements it.
+// If v is not addressable, the pointer is to a copy of v.
+// Nil pointers are never returned.
+func asInterface[T any](v reflect.Value) (_ T, ok bool) {
+	if !v.IsValid() || v.Kind() == reflect.Pointer && v.IsNil() {
+		return *new(T), false
+	}
+	if x, ok := v.Interface().(T); ok {
+		return x, true
+	}
+	if v.Kind() == reflect.Pointer || !reflect.PointerTo(v.Type()).Implements(reflect.TypeFor[T]()) {
+		return *new(T), false
+	}
+	if !v.CanAddr() {
+		v2 := reflect.New(v.Type()).Elem()
+		v2.Set(v)
+		v = v2
+	}
+	return v.Addr().Interface().(T), true
+}
+
+// paramName returns the name of the i'th parameter of stmt for error messages.
+func paramName(stmt *sqlite.Stmt, i int) string {
+	if name := stmt.BindParamName(i); name != "" {
+		return name
+	}
+	return fmt.Sprintf("?%d", i)
+}
+
+func setNamed(stmt *sqlite.Stmt, provided bitset, flags uint8, args map[string]any, structArg any) error {
+	if len(args) == 0 && structArg == nil {
 		return nil
 	}
+	var structValue reflect.Value
+	var fields []structField
+	if structArg != nil {
+		structValue = reflect.ValueOf(structArg)
+		for structValue.Kind() == reflect.Pointer && !structValue.IsNil() {
+			structValue = structValue.Elem()
+		}
+		if structValue.Kind() != reflect.Struct {
+			return fmt.Errorf("sqlitex: NamedStruct is %T, not a struct", structArg)
+		}
+		fields = structFields(structValue.Type())
+	}
 	var unused map[string]struct{}
 	if flags&forbidExtra != 0 {
 		unused = make(map[string]struct{}, len(args))
@@ -347,17 +436,28 @@ func setNamed(stmt *sqlite.Stmt, provided bitset, flags uint8, args map[string]a
 		if name == "" {
 			continue
 		}
-		arg, present := args[name]
-		if !present {
+		var v reflect.Value
+		if arg, present := args[name]; present {
+			delete(unused, name)
+			v = reflect.ValueOf(arg)
+		} else if index := findField(fields, name[1:]); index != nil {
+			var err error
+			v, err = structValue.FieldByIndexErr(index)
+			if err != nil {
+				// Field is in a nil embedded struct.
+				v = reflect.Value{}
+			}
+		} else {
 			if flags&forbidMissing != 0 {
 				// TODO(maybe): Check provided as well?
 				return fmt.Errorf("missing parameter %s", name)
 			}
 			continue
 		}
-		delete(unused, name)
 		provided.set(i - 1)
-		setArg(stmt, i, reflect.ValueOf(arg))
+		if err := setArg(stmt, i, flags, v); err != nil {
+			return err
+		}
 	}
 	if len(unused) > 0 {
 		return fmt.Errorf("%w: unknown argument %s", sqlite.ResultRange.ToError(), minStringInSet(unused))
@@ -403,7 +503,7 @@ func ExecuteScript(conn *sqlite.Conn, queries string, opts *ExecOptions) (err er
 		}
 		usedBytes := len(queries) - trailingBytes
 		queries = queries[usedBytes:]
-		err = exec(stmt, forbidMissing, opts)
+		err = exec(stmt, forbidMissing|forbidUnknownTypes, opts)
 		stmt.Finalize()
 		if err != nil {
 			return err
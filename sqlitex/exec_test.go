@@ -225,6 +225,126 @@ func TestExecuteArgsErrors(t *testing.T) {
 			t.Errorf("code = %v; want %v", got, want)
 		}
 	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		err := Execute(conn, `SELECT ?;`, &ExecOptions{
+			Args: []any{struct{}{}},
+		})
+		t.Log(err)
+		if err == nil {
+			t.Error("Execute did not return an error")
+		}
+	})
+
+	t.Run("MissingNamedStructField", func(t *testing.T) {
+		err := Execute(conn, `SELECT :foo;`, &ExecOptions{
+			NamedStruct: struct{ Bar int }{},
+		})
+		t.Log(err)
+		if err == nil {
+			t.Error("Execute did not return an error")
+		}
+	})
+}
+
+// hexBinder binds an integer as a hexadecimal string.
+type hexBinder int
+
+func (h hexBinder) BindParam(stmt *sqlite.Stmt, param int) error {
+	stmt.BindText(param, fmt.Sprintf("%#x", int(h)))
+	return nil
+}
+
+type textEnum int
+
+func (e textEnum) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("enum%d", int(e))), nil
+}
+
+type ptrTextEnum struct {
+	n int
+}
+
+func (e *ptrTextEnum) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("ptrenum%d", e.n)), nil
+}
+
+func TestExecuteNamedStruct(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	type Embedded struct {
+		Extra string `sqlite:"extra"`
+	}
+	type wrapped struct {
+		X float64
+	}
+	nickname := "Bobby"
+	arg := &struct {
+		*Embedded
+		ID       int    `sqlite:"id"`
+		Name     string `sqlite:"full_name"`
+		Nickname *string
+		Missing  *string
+		Hex      hexBinder
+		Data     []byte
+		Unused   wrapped
+	}{
+		ID:       2,
+		Name:     "Bob Jones",
+		Nickname: &nickname,
+		Hex:      255,
+		Data:     []byte{1, 2},
+	}
+	var got []string
+	err = Execute(conn, `SELECT :id, :full_name, @nickname, $missing, :hex, hex(:data), :extra, :override;`, &ExecOptions{
+		Named:       map[string]any{":override": textEnum(3)},
+		NamedStruct: arg,
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			for i := 0; i < stmt.ColumnCount(); i++ {
+				if stmt.ColumnIsNull(i) {
+					got = append(got, "NULL")
+				} else {
+					got = append(got, stmt.ColumnText(i))
+				}
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"2", "Bob Jones", "Bobby", "NULL", "0xff", "0102", "NULL", "3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
+
+	// textEnum has an integer kind, so it was bound as an integer above,
+	// but a struct is bound with its MarshalText method.
+	got = nil
+	// MarshalText methods with pointer receivers are used too.
+	err = Execute(conn, `SELECT :enum, :ptr_value, :ptr;`, &ExecOptions{
+		Named: map[string]any{
+			":enum":      struct{ textEnum }{4},
+			":ptr_value": ptrTextEnum{5},
+			":ptr":       &ptrTextEnum{6},
+		},
+		ResultFunc: func(stmt *sqlite.Stmt) error {
+			for i := 0; i < stmt.ColumnCount(); i++ {
+				got = append(got, stmt.ColumnText(i))
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"enum4", "ptrenum5", "ptrenum6"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q; want %q", got, want)
+	}
 }
 
 func TestExecScript(t *testing.T) {
@@ -0,0 +1,176 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// BindJSON binds the JSON encoding of v to a numbered stmt parameter as text.
+// v is encoded with [json.Marshal].
+// SQLite's JSON functions accept the text as JSON.
+//
+// Parameter indices start at 1.
+func BindJSON(stmt *sqlite.Stmt, param int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("sqlitex: bind %s as json: %w", paramName(stmt, param), err)
+	}
+	stmt.BindText(param, string(data))
+	return nil
+}
+
+// BindJSONB binds the JSON encoding of v to a numbered stmt parameter
+// as a blob in SQLite's binary [JSONB] format.
+// v is encoded with [json.Marshal].
+//
+// Parameter indices start at 1.
+//
+// [JSONB]: https://sqlite.org/jsonb.html
+func BindJSONB(stmt *sqlite.Stmt, param int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("sqlitex: bind %s as jsonb: %w", paramName(stmt, param), err)
+	}
+	b, _, err := appendJSONB(nil, data)
+	if err != nil {
+		return fmt.Errorf("sqlitex: bind %s as jsonb: %w", paramName(stmt, param), err)
+	}
+	stmt.BindBytes(param, b)
+	return nil
+}
+
+// ColumnJSON decodes a query result value into the value pointed to by v
+// using [json.Unmarshal].
+// Text is decoded as JSON text,
+// blobs are decoded as [JSONB],
+// numbers are decoded as JSON numbers,
+// and NULL is decoded as a JSON null.
+//
+// Column indices start at 0.
+//
+// [JSONB]: https://sqlite.org/jsonb.html
+func ColumnJSON(stmt *sqlite.Stmt, col int, v any) error {
+	data, err := jsonData(stmt.ColumnType(col), func() string {
+		return stmt.ColumnText(col)
+	}, func() []byte {
+		buf := make([]byte, stmt.ColumnLen(col))
+		stmt.ColumnBytes(col, buf)
+		return buf
+	})
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("sqlitex: column %q as json: %w", stmt.ColumnName(col), err)
+	}
+	return nil
+}
+
+// ValueJSON decodes an SQL function argument into the value pointed to by v
+// using [json.Unmarshal].
+// Values are converted the same way as [ColumnJSON].
+func ValueJSON(arg sqlite.Value, v any) error {
+	data, err := jsonData(arg.Type(), arg.Text, arg.Blob)
+	if err == nil {
+		err = json.Unmarshal(data, v)
+	}
+	if err != nil {
+		return fmt.Errorf("sqlitex: value as json: %w", err)
+	}
+	return nil
+}
+
+// jsonData returns the JSON text for a value
+// with the given type and accessors.
+func jsonData(typ sqlite.ColumnType, text func() string, blob func() []byte) ([]byte, error) {
+	switch typ {
+	case sqlite.TypeNull:
+		return []byte("null"), nil
+	case sqlite.TypeBlob:
+		data, rest, err := appendJSONFromJSONB(nil, blob())
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("trailing data after JSONB element")
+		}
+		return data, nil
+	default:
+		return []byte(text()), nil
+	}
+}
+
+// JSONFunctionImpl describes an [application-defined SQL function]
+// that accepts and returns JSON.
+// See [CreateJSONFunction].
+//
+// [application-defined SQL function]: https://sqlite.org/appfunc.html
+type JSONFunctionImpl struct {
+	// NArgs is the required number of arguments that the function accepts.
+	// If NArgs is negative, then the function is variadic.
+	NArgs int
+
+	// Func is called when the function is invoked in SQL.
+	// Each argument is converted to JSON text the same way as [ValueJSON]
+	// and must be well-formed JSON.
+	// Text arguments marked with [sqlite.JSONSubtype],
+	// like the results of SQLite's JSON functions,
+	// are passed through without being checked again.
+	// The returned value is encoded with [json.Marshal],
+	// unless it is an untyped nil, in which case the function returns NULL.
+	Func func(ctx sqlite.Context, args []json.RawMessage) (any, error)
+
+	// Deterministic and AllowIndirect have the same meaning
+	// as in [sqlite.FunctionImpl].
+	Deterministic bool
+	AllowIndirect bool
+}
+
+// CreateJSONFunction registers a Go function with SQLite
+// that accepts and returns JSON.
+// The function's results are marked as JSON
+// (see [sqlite.JSONValue]),
+// so passing them to SQLite's json_* functions
+// inserts them as JSON values rather than as strings.
+func CreateJSONFunction(conn *sqlite.Conn, name string, impl *JSONFunctionImpl) error {
+	if impl.Func == nil {
+		return fmt.Errorf("sqlitex: create json function %s: Func not set", name)
+	}
+	return conn.CreateFunction(name, &sqlite.FunctionImpl{
+		NArgs:           impl.NArgs,
+		Deterministic:   impl.Deterministic,
+		AllowIndirect:   impl.AllowIndirect,
+		SubtypeConsumer: true,
+		ResultSubtype:   true,
+		Scalar: func(ctx sqlite.Context, args []sqlite.Value) (sqlite.Value, error) {
+			jsonArgs := make([]json.RawMessage, len(args))
+			for i, arg := range args {
+				data, err := jsonData(arg.Type(), arg.Text, arg.Blob)
+				if err != nil {
+					return sqlite.Value{}, fmt.Errorf("%s: argument %d: %w", name, i+1, err)
+				}
+				if arg.Subtype() != sqlite.JSONSubtype && !json.Valid(data) {
+					return sqlite.Value{}, fmt.Errorf("%s: argument %d: malformed JSON", name, i+1)
+				}
+				jsonArgs[i] = data
+			}
+			result, err := impl.Func(ctx, jsonArgs)
+			if err != nil {
+				return sqlite.Value{}, err
+			}
+			if result == nil {
+				return sqlite.Value{}, nil
+			}
+			data, err := json.Marshal(result)
+			if err != nil {
+				return sqlite.Value{}, fmt.Errorf("%s: %w", name, err)
+			}
+			return sqlite.JSONValue(string(data)), nil
+		},
+	})
+}
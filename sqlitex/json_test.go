@@ -0,0 +1,155 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+)
+
+func TestJSON(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	type doc struct {
+		Name  string         `json:"name"`
+		Tags  []string       `json:"tags"`
+		Score float64        `json:"score"`
+		Count int64          `json:"count"`
+		Extra map[string]any `json:"extra"`
+	}
+	value := doc{
+		Name:  "café \"quoted\"\n",
+		Tags:  []string{"a", "b"},
+		Score: 1.5,
+		Count: 1 << 40,
+		Extra: map[string]any{"ok": true, "none": nil},
+	}
+	const wantJSON = `{"name":"café \"quoted\"\n","tags":["a","b"],"score":1.5,"count":1099511627776,"extra":{"none":null,"ok":true}}`
+
+	bindFuncs := []struct {
+		name     string
+		bind     func(stmt *sqlite.Stmt, param int, v any) error
+		wantType sqlite.ColumnType
+	}{
+		{"BindJSON", BindJSON, sqlite.TypeText},
+		{"BindJSONB", BindJSONB, sqlite.TypeBlob},
+	}
+	for _, test := range bindFuncs {
+		t.Run(test.name, func(t *testing.T) {
+			stmt, _, err := conn.PrepareTransient(`SELECT ?1, json(?1), json_extract(?1, '$.tags[1]');`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stmt.Finalize()
+			if err := test.bind(stmt, 1, value); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := stmt.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if got := stmt.ColumnType(0); got != test.wantType {
+				t.Errorf("type = %v; want %v", got, test.wantType)
+			}
+			if got := stmt.ColumnText(1); got != wantJSON {
+				t.Errorf("json(?1) = %s; want %s", got, wantJSON)
+			}
+			if got, want := stmt.ColumnText(2), "b"; got != want {
+				t.Errorf("json_extract(?1, '$.tags[1]') = %q; want %q", got, want)
+			}
+			var got doc
+			if err := ColumnJSON(stmt, 0, &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(value, got); diff != "" {
+				t.Errorf("ColumnJSON(...) (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("ColumnJSON", func(t *testing.T) {
+		stmt, _, err := conn.PrepareTransient(`SELECT jsonb(:json5), NULL, 42;`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		stmt.SetText(":json5", `{a: 0x1F, b: .5, c: 'it\'s\x41', d: [+2, 1.]}`)
+		if _, err := stmt.Step(); err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]any
+		if err := ColumnJSON(stmt, 0, &got); err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]any{
+			"a": 31.0,
+			"b": 0.5,
+			"c": "it'sA",
+			"d": []any{2.0, 1.0},
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("ColumnJSON(jsonb(...)) (-want +got):\n%s", diff)
+		}
+
+		ptr := new(int)
+		if err := ColumnJSON(stmt, 1, &ptr); err != nil {
+			t.Error(err)
+		} else if ptr != nil {
+			t.Errorf("ColumnJSON(NULL) = %d; want nil", *ptr)
+		}
+		var n int
+		if err := ColumnJSON(stmt, 2, &n); err != nil {
+			t.Error(err)
+		} else if n != 42 {
+			t.Errorf("ColumnJSON(42) = %d; want 42", n)
+		}
+	})
+
+	t.Run("Function", func(t *testing.T) {
+		err := CreateJSONFunction(conn, "double_all", &JSONFunctionImpl{
+			NArgs:         1,
+			Deterministic: true,
+			Func: func(ctx sqlite.Context, args []json.RawMessage) (any, error) {
+				var nums []float64
+				if err := json.Unmarshal(args[0], &nums); err != nil {
+					return nil, err
+				}
+				for i := range nums {
+					nums[i] *= 2
+				}
+				return nums, nil
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ResultText(conn.Prep(`SELECT json_array(double_all('[1, 2]'), double_all(jsonb('[3]')));`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `[[2,4],[6]]`; got != want {
+			t.Errorf("json_array(double_all(...), ...) = %s; want %s", got, want)
+		}
+		got, err = ResultText(conn.Prep(`SELECT double_all(json(' [4] ')) ->> 0;`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `8`; got != want {
+			t.Errorf("double_all(json(' [4] ')) ->> 0 = %s; want %s", got, want)
+		}
+		if _, err := ResultText(conn.Prep(`SELECT double_all('not json');`)); err == nil {
+			t.Error("double_all('not json') did not return an error")
+		}
+	})
+}
@@ -0,0 +1,331 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"unicode/utf8"
+)
+
+// JSONB element types.
+// See https://sqlite.org/jsonb.html for details.
+const (
+	jsonbNull    = 0
+	jsonbTrue    = 1
+	jsonbFalse   = 2
+	jsonbInt     = 3
+	jsonbInt5    = 4
+	jsonbFloat   = 5
+	jsonbFloat5  = 6
+	jsonbText    = 7
+	jsonbTextJ   = 8
+	jsonbText5   = 9
+	jsonbTextRaw = 10
+	jsonbArray   = 11
+	jsonbObject  = 12
+)
+
+// jsonMaxDepth is the deepest nesting of arrays and objects
+// that the JSONB codec accepts.
+// It is the same as SQLite's JSON_MAX_DEPTH.
+const jsonMaxDepth = 1000
+
+// errJSONDepth is returned for JSON or JSONB
+// nested deeper than jsonMaxDepth.
+var errJSONDepth = fmt.Errorf("JSON nested more than %d levels deep", jsonMaxDepth)
+
+// appendJSONBHeader appends the header of a JSONB element
+// with the given type and payload size to dst.
+func appendJSONBHeader(dst []byte, typ byte, size int) []byte {
+	switch {
+	case size <= 11:
+		return append(dst, byte(size)<<4|typ)
+	case size <= math.MaxUint8:
+		return append(dst, 0xc0|typ, byte(size))
+	case size <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd0|typ), uint16(size))
+	case uint64(size) <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xe0|typ), uint32(size))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xf0|typ), uint64(size))
+	}
+}
+
+// appendJSONB appends the JSONB encoding of the first JSON value in data to dst.
+// data must be valid JSON, like the output of [json.Marshal].
+// appendJSONB returns the bytes of data after the value.
+func appendJSONB(dst []byte, data []byte) (_ []byte, rest []byte, err error) {
+	return appendJSONBValue(dst, data, 0)
+}
+
+// appendJSONBValue is appendJSONB for a value
+// nested inside depth arrays and objects.
+func appendJSONBValue(dst []byte, data []byte, depth int) (_ []byte, rest []byte, err error) {
+	data = skipJSONSpace(data)
+	if len(data) == 0 {
+		return dst, data, errors.New("unexpected end of JSON")
+	}
+	switch c := data[0]; {
+	case c == 'n' && bytes.HasPrefix(data, []byte("null")):
+		return appendJSONBHeader(dst, jsonbNull, 0), data[len("null"):], nil
+	case c == 't' && bytes.HasPrefix(data, []byte("true")):
+		return appendJSONBHeader(dst, jsonbTrue, 0), data[len("true"):], nil
+	case c == 'f' && bytes.HasPrefix(data, []byte("false")):
+		return appendJSONBHeader(dst, jsonbFalse, 0), data[len("false"):], nil
+	case c == '"':
+		typ := byte(jsonbText)
+		i := 1
+		for ; i < len(data) && data[i] != '"'; i++ {
+			if data[i] == '\\' {
+				typ = jsonbTextJ
+				i++
+			}
+		}
+		if i >= len(data) {
+			return dst, data, errors.New("unterminated JSON string")
+		}
+		dst = appendJSONBHeader(dst, typ, i-1)
+		return append(dst, data[1:i]...), data[i+1:], nil
+	case c == '-' || '0' <= c && c <= '9':
+		typ := byte(jsonbInt)
+		i := 1
+		for ; i < len(data); i++ {
+			c := data[i]
+			if c == '.' || c == 'e' || c == 'E' {
+				typ = jsonbFloat
+			} else if !('0' <= c && c <= '9' || c == '+' || c == '-') {
+				break
+			}
+		}
+		dst = appendJSONBHeader(dst, typ, i)
+		return append(dst, data[:i]...), data[i:], nil
+	case c == '[' || c == '{':
+		if depth >= jsonMaxDepth {
+			return dst, data, errJSONDepth
+		}
+		typ, end := byte(jsonbArray), byte(']')
+		if c == '{' {
+			typ, end = jsonbObject, '}'
+		}
+		var payload []byte
+		rest := skipJSONSpace(data[1:])
+		for n := 0; len(rest) == 0 || rest[0] != end; n++ {
+			if n > 0 {
+				if len(rest) == 0 || rest[0] != ',' {
+					return dst, data, fmt.Errorf("expected ',' or '%c' in JSON", end)
+				}
+				rest = rest[1:]
+			}
+			var err error
+			payload, rest, err = appendJSONBValue(payload, rest, depth+1)
+			if err != nil {
+				return dst, data, err
+			}
+			rest = skipJSONSpace(rest)
+			if typ == jsonbObject {
+				if len(rest) == 0 || rest[0] != ':' {
+					return dst, data, errors.New("expected ':' in JSON object")
+				}
+				payload, rest, err = appendJSONBValue(payload, rest[1:], depth+1)
+				if err != nil {
+					return dst, data, err
+				}
+				rest = skipJSONSpace(rest)
+			}
+		}
+		dst = appendJSONBHeader(dst, typ, len(payload))
+		return append(dst, payload...), rest[1:], nil
+	default:
+		return dst, data, fmt.Errorf("invalid character %q in JSON", c)
+	}
+}
+
+func skipJSONSpace(data []byte) []byte {
+	for len(data) > 0 && (data[0] == ' ' || data[0] == '\t' || data[0] == '\n' || data[0] == '\r') {
+		data = data[1:]
+	}
+	return data
+}
+
+// appendJSONFromJSONB appends the JSON text of the first JSONB element in b to dst.
+// It returns the bytes of b after the element.
+func appendJSONFromJSONB(dst []byte, b []byte) (_ []byte, rest []byte, err error) {
+	return appendJSONFromJSONBElement(dst, b, 0)
+}
+
+// appendJSONFromJSONBElement is appendJSONFromJSONB for an element
+// nested inside depth arrays and objects.
+func appendJSONFromJSONBElement(dst []byte, b []byte, depth int) (_ []byte, rest []byte, err error) {
+	if len(b) == 0 {
+		return dst, b, errors.New("unexpected end of JSONB")
+	}
+	typ := b[0] & 0x0f
+	size := uint64(b[0] >> 4)
+	hdr := 1
+	switch size {
+	case 12:
+		hdr = 2
+	case 13:
+		hdr = 3
+	case 14:
+		hdr = 5
+	case 15:
+		hdr = 9
+	}
+	if len(b) < hdr {
+		return dst, b, errors.New("unexpected end of JSONB")
+	}
+	switch hdr {
+	case 2:
+		size = uint64(b[1])
+	case 3:
+		size = uint64(binary.BigEndian.Uint16(b[1:]))
+	case 5:
+		size = uint64(binary.BigEndian.Uint32(b[1:]))
+	case 9:
+		size = binary.BigEndian.Uint64(b[1:])
+	}
+	if size > uint64(len(b)-hdr) {
+		return dst, b, errors.New("JSONB element larger than blob")
+	}
+	payload := b[hdr : hdr+int(size)]
+	rest = b[hdr+int(size):]
+
+	switch typ {
+	case jsonbNull:
+		return append(dst, "null"...), rest, nil
+	case jsonbTrue:
+		return append(dst, "true"...), rest, nil
+	case jsonbFalse:
+		return append(dst, "false"...), rest, nil
+	case jsonbInt, jsonbFloat:
+		return append(dst, payload...), rest, nil
+	case jsonbInt5:
+		if i, err := strconv.ParseInt(string(payload), 0, 64); err == nil {
+			return strconv.AppendInt(dst, i, 10), rest, nil
+		}
+		i, ok := new(big.Int).SetString(string(payload), 0)
+		if !ok {
+			return dst, b, fmt.Errorf("invalid JSONB integer %q", payload)
+		}
+		return i.Append(dst, 10), rest, nil
+	case jsonbFloat5:
+		f, err := strconv.ParseFloat(string(payload), 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
+			return dst, b, fmt.Errorf("invalid JSONB float %q", payload)
+		}
+		// Follow SQLite's json() function for values that JSON can't represent.
+		switch {
+		case math.IsNaN(f):
+			return append(dst, "null"...), rest, nil
+		case math.IsInf(f, 1):
+			return append(dst, "9e999"...), rest, nil
+		case math.IsInf(f, -1):
+			return append(dst, "-9e999"...), rest, nil
+		}
+		return strconv.AppendFloat(dst, f, 'g', -1, 64), rest, nil
+	case jsonbText, jsonbTextJ:
+		dst = append(dst, '"')
+		dst = append(dst, payload...)
+		return append(dst, '"'), rest, nil
+	case jsonbText5:
+		dst, err = appendJSON5String(dst, payload)
+		return dst, rest, err
+	case jsonbTextRaw:
+		s, err := json.Marshal(string(payload))
+		if err != nil {
+			return dst, b, err
+		}
+		return append(dst, s...), rest, nil
+	case jsonbArray, jsonbObject:
+		if depth >= jsonMaxDepth {
+			return dst, b, errJSONDepth
+		}
+		open, end := byte('['), byte(']')
+		if typ == jsonbObject {
+			open, end = '{', '}'
+		}
+		dst = append(dst, open)
+		for n := 0; len(payload) > 0; n++ {
+			if n > 0 {
+				if typ == jsonbObject && n%2 == 1 {
+					dst = append(dst, ':')
+				} else {
+					dst = append(dst, ',')
+				}
+			}
+			dst, payload, err = appendJSONFromJSONBElement(dst, payload, depth+1)
+			if err != nil {
+				return dst, b, err
+			}
+		}
+		return append(dst, end), rest, nil
+	default:
+		return dst, b, fmt.Errorf("invalid JSONB element type %d", typ)
+	}
+}
+
+// appendJSON5String appends the JSON string for
+// the body of a JSON5 string with escapes to dst.
+func appendJSON5String(dst []byte, s []byte) ([]byte, error) {
+	dst = append(dst, '"')
+	for len(s) > 0 {
+		c := s[0]
+		switch {
+		case c == '"':
+			dst = append(dst, `\"`...)
+			s = s[1:]
+			continue
+		case c != '\\':
+			dst = append(dst, c)
+			s = s[1:]
+			continue
+		case len(s) < 2:
+			return dst, errors.New("invalid JSONB string escape")
+		}
+		switch s[1] {
+		case '\'':
+			dst = append(dst, '\'')
+			s = s[2:]
+		case 'v':
+			dst = append(dst, `\u000b`...)
+			s = s[2:]
+		case '0':
+			dst = append(dst, `\u0000`...)
+			s = s[2:]
+		case 'x':
+			if len(s) < 4 {
+				return dst, errors.New("invalid JSONB string escape")
+			}
+			dst = append(dst, `\u00`...)
+			dst = append(dst, s[2:4]...)
+			s = s[4:]
+		case '\n':
+			// Line continuation.
+			s = s[2:]
+		case '\r':
+			s = s[2:]
+			if len(s) > 0 && s[0] == '\n' {
+				s = s[1:]
+			}
+		default:
+			if r, n := utf8.DecodeRune(s[1:]); r == '\u2028' || r == '\u2029' {
+				// Line continuation.
+				s = s[1+n:]
+				continue
+			}
+			// JSON escape sequences are the same in JSON5.
+			dst = append(dst, s[:2]...)
+			s = s[2:]
+		}
+	}
+	return append(dst, '"'), nil
+}
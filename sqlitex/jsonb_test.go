@@ -0,0 +1,310 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+)
+
+func TestAppendJSONBHeader(t *testing.T) {
+	tests := []struct {
+		size int
+		want []byte
+	}{
+		{0, []byte{0x07}},
+		{11, []byte{0xb7}},
+		{12, []byte{0xc7, 12}},
+		{255, []byte{0xc7, 0xff}},
+		{256, []byte{0xd7, 0x01, 0x00}},
+		{65535, []byte{0xd7, 0xff, 0xff}},
+		{65536, []byte{0xe7, 0x00, 0x01, 0x00, 0x00}},
+		{1 << 32, []byte{0xf7, 0, 0, 0, 0x01, 0, 0, 0, 0}},
+	}
+	for _, test := range tests {
+		if got := appendJSONBHeader(nil, jsonbText, test.size); !bytes.Equal(got, test.want) {
+			t.Errorf("appendJSONBHeader(nil, jsonbText, %d) = %#v; want %#v", test.size, got, test.want)
+		}
+	}
+}
+
+func TestAppendJSONFromJSONB(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+		want string
+	}{
+		{"Header12", []byte{0xc7, 3, 'a', 'b', 'c'}, `"abc"`},
+		{"Header13", []byte{0xd7, 0, 3, 'a', 'b', 'c'}, `"abc"`},
+		{"Header14", []byte{0xe7, 0, 0, 0, 3, 'a', 'b', 'c'}, `"abc"`},
+		{"Header15", []byte{0xf7, 0, 0, 0, 0, 0, 0, 0, 3, 'a', 'b', 'c'}, `"abc"`},
+		{"Text5", jsonbElement(jsonbText5, `\x41\'\v\0"`), `"\u0041'\u000b\u0000\""`},
+		{"Text5Continuation", jsonbElement(jsonbText5, "a\\\nb\\\r\nc\\ d"), `"abcd"`},
+		{"TextRaw", jsonbElement(jsonbTextRaw, "a\"b\\c\n"), `"a\"b\\c\n"`},
+		{"Int5Hex", jsonbElement(jsonbInt5, "0x1F"), `31`},
+		{"Int5Negative", jsonbElement(jsonbInt5, "-0x10"), `-16`},
+		{"Int5Plus", jsonbElement(jsonbInt5, "+7"), `7`},
+		{"Int5Over64Bits", jsonbElement(jsonbInt5, "0x1FFFFFFFFFFFFFFFFF"), `590295810358705651711`},
+		{"Float5", jsonbElement(jsonbFloat5, ".5"), `0.5`},
+		{"NaN", jsonbElement(jsonbFloat5, "NaN"), `null`},
+		{"Infinity", jsonbElement(jsonbFloat5, "Infinity"), `9e999`},
+		{"NegativeInfinity", jsonbElement(jsonbFloat5, "-Infinity"), `-9e999`},
+		{"Overflow", jsonbElement(jsonbFloat5, "1e999"), `9e999`},
+		{
+			"Object",
+			jsonbElement(jsonbObject, string(jsonbElement(jsonbText, "a"))+string(jsonbElement(jsonbArray, "\x13\x31\x00"))),
+			`{"a":[1,null]}`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, rest, err := appendJSONFromJSONB(nil, test.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != test.want {
+				t.Errorf("appendJSONFromJSONB(nil, %#v) = %s; want %s", test.b, got, test.want)
+			}
+			if len(rest) > 0 {
+				t.Errorf("rest = %#v; want empty", rest)
+			}
+			if !json.Valid(got) {
+				t.Errorf("%s is not valid JSON", got)
+			}
+		})
+	}
+}
+
+func TestAppendJSONFromJSONBErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"Empty", []byte{}},
+		{"TruncatedHeader12", []byte{0xc7}},
+		{"TruncatedHeader13", []byte{0xd7, 0}},
+		{"TruncatedHeader14", []byte{0xe7, 0, 0, 0}},
+		{"TruncatedHeader15", []byte{0xf7, 0, 0, 0, 0, 0, 0, 0}},
+		{"TruncatedPayload", []byte{0x37, 'a', 'b'}},
+		{"HugeSize", []byte{0xf7, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 'a'}},
+		{"InvalidType", []byte{0x0d}},
+		{"TruncatedChild", []byte{0x2b, 0x37, 'a'}},
+		{"InvalidChild", []byte{0x1b, 0x0e}},
+		{"Int5", jsonbElement(jsonbInt5, "0xZZ")},
+		{"Float5", jsonbElement(jsonbFloat5, "1.5.5")},
+		{"Text5Escape", jsonbElement(jsonbText5, `a\`)},
+		{"Text5HexEscape", jsonbElement(jsonbText5, `\x4`)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, _, err := appendJSONFromJSONB(nil, test.b)
+			if err == nil {
+				t.Errorf("appendJSONFromJSONB(nil, %#v) = %s, <nil>; want error", test.b, got)
+			}
+		})
+	}
+}
+
+// TestJSONBSQLite checks that the JSONB codec
+// agrees with SQLite's JSON functions.
+func TestJSONBSQLite(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	inputs := []string{
+		`null`,
+		`true`,
+		`false`,
+		`0`,
+		`-12345678901234567890`,
+		`1.5e-7`,
+		`""`,
+		`"abcdefghijk"`,
+		`"abcdefghijkl"`,
+		`"` + strings.Repeat("x", 300) + `"`,
+		`"` + strings.Repeat("y", 70000) + `"`,
+		`"tab\tquote\"unicodeé"`,
+		`[]`,
+		`{}`,
+		`[1, [2, [3, {"a": null}]], "s"]`,
+		`{"k": ` + strings.Repeat(`[`, 8) + strings.Repeat(`]`, 8) + `}`,
+		`{"long": "` + strings.Repeat("z", 1000) + `", "n": -0.25}`,
+	}
+	for _, input := range inputs {
+		name := input
+		if len(name) > 40 {
+			name = name[:40]
+		}
+
+		// Go encoder, SQLite decoder.
+		b, rest, err := appendJSONB(nil, []byte(input))
+		if err != nil {
+			t.Errorf("appendJSONB(nil, %s): %v", name, err)
+			continue
+		}
+		if len(rest) > 0 {
+			t.Errorf("appendJSONB(nil, %s) rest = %q; want empty", name, rest)
+		}
+		sqliteJSON, err := sqliteJSONResult(conn, "SELECT json(?1);", func(stmt *sqlite.Stmt) {
+			stmt.BindBytes(1, b)
+		})
+		if err != nil {
+			t.Errorf("json(appendJSONB(%s)): %v", name, err)
+			continue
+		}
+		if diff := cmp.Diff(decodeJSON(t, input), decodeJSON(t, sqliteJSON)); diff != "" {
+			t.Errorf("json(appendJSONB(%s)) (-want +got):\n%s", name, diff)
+		}
+
+		// SQLite encoder, Go decoder.
+		sqliteJSONB, err := sqliteBlobResult(conn, "SELECT jsonb(?1);", func(stmt *sqlite.Stmt) {
+			stmt.BindText(1, input)
+		})
+		if err != nil {
+			t.Errorf("jsonb(%s): %v", name, err)
+			continue
+		}
+		got, rest, err := appendJSONFromJSONB(nil, sqliteJSONB)
+		if err != nil {
+			t.Errorf("appendJSONFromJSONB(jsonb(%s)): %v", name, err)
+			continue
+		}
+		if len(rest) > 0 {
+			t.Errorf("appendJSONFromJSONB(jsonb(%s)) rest = %#v; want empty", name, rest)
+		}
+		if diff := cmp.Diff(decodeJSON(t, input), decodeJSON(t, string(got))); diff != "" {
+			t.Errorf("appendJSONFromJSONB(jsonb(%s)) (-want +got):\n%s", name, diff)
+		}
+	}
+
+	// SQLite produces the JSON5 element types from JSON5 input.
+	// (SQLite's json() function converts the \v escape to a tab,
+	// so it is only tested above.)
+	queries := []string{
+		`SELECT jsonb('[''single'', "\x41\''", 0x1F, +5, .5, Infinity, -Infinity, NaN]');`,
+		`SELECT jsonb_array('quote" backslash\ newline' || char(10), 'plain');`,
+		`SELECT jsonb_object('key"', 'value\');`,
+	}
+	for _, query := range queries {
+		b, err := sqliteBlobResult(conn, query, func(*sqlite.Stmt) {})
+		if err != nil {
+			t.Errorf("%s: %v", query, err)
+			continue
+		}
+		got, _, err := appendJSONFromJSONB(nil, b)
+		if err != nil {
+			t.Errorf("appendJSONFromJSONB(%s): %v", query, err)
+			continue
+		}
+		want, err := sqliteJSONResult(conn, "SELECT json(?1);", func(stmt *sqlite.Stmt) {
+			stmt.BindBytes(1, b)
+		})
+		if err != nil {
+			t.Errorf("json(%s): %v", query, err)
+			continue
+		}
+		if diff := cmp.Diff(decodeJSON(t, want), decodeJSON(t, string(got))); diff != "" {
+			t.Errorf("appendJSONFromJSONB(%s) (-json() +got):\n%s", query, diff)
+		}
+	}
+}
+
+func TestJSONBMaxDepth(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	for _, depth := range []int{jsonMaxDepth, jsonMaxDepth + 1} {
+		input := strings.Repeat("[", depth) + strings.Repeat("]", depth)
+		_, sqliteErr := sqliteBlobResult(conn, "SELECT jsonb(?1);", func(stmt *sqlite.Stmt) {
+			stmt.BindText(1, input)
+		})
+		wantErr := depth > jsonMaxDepth
+		if gotErr := sqliteErr != nil; gotErr != wantErr {
+			t.Fatalf("jsonb(depth %d) error = %v; want error = %t", depth, sqliteErr, wantErr)
+		}
+
+		b, _, err := appendJSONB(nil, []byte(input))
+		if gotErr := err != nil; gotErr != wantErr {
+			t.Errorf("appendJSONB(nil, depth %d) error = %v; want error = %t", depth, err, wantErr)
+		}
+		if wantErr {
+			// Build the element by hand, since neither encoder will.
+			b = nil
+			for i := 0; i < depth; i++ {
+				b = append(appendJSONBHeader(nil, jsonbArray, len(b)), b...)
+			}
+		}
+		_, _, err = appendJSONFromJSONB(nil, b)
+		if gotErr := err != nil; gotErr != wantErr {
+			t.Errorf("appendJSONFromJSONB(nil, depth %d) error = %v; want error = %t", depth, err, wantErr)
+		}
+	}
+}
+
+// jsonbElement returns a JSONB element with the given type and payload.
+func jsonbElement(typ byte, payload string) []byte {
+	return append(appendJSONBHeader(nil, typ, len(payload)), payload...)
+}
+
+// decodeJSON decodes JSON text for comparison,
+// keeping numbers in their text form.
+func decodeJSON(tb testing.TB, s string) any {
+	tb.Helper()
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		tb.Fatalf("decode %.40s: %v", s, err)
+	}
+	return v
+}
+
+// sqliteResult runs a query that returns a single row
+// and returns the first column's text and blob values.
+func sqliteResult(conn *sqlite.Conn, query string, bind func(*sqlite.Stmt)) (text string, blob []byte, err error) {
+	stmt, _, err := conn.PrepareTransient(query)
+	if err != nil {
+		return "", nil, err
+	}
+	defer stmt.Finalize()
+	bind(stmt)
+	if _, err := stmt.Step(); err != nil {
+		return "", nil, err
+	}
+	if stmt.ColumnType(0) == sqlite.TypeBlob {
+		blob = make([]byte, stmt.ColumnLen(0))
+		stmt.ColumnBytes(0, blob)
+		return "", blob, nil
+	}
+	return stmt.ColumnText(0), nil, nil
+}
+
+func sqliteJSONResult(conn *sqlite.Conn, query string, bind func(*sqlite.Stmt)) (string, error) {
+	text, _, err := sqliteResult(conn, query, bind)
+	return text, err
+}
+
+func sqliteBlobResult(conn *sqlite.Conn, query string, bind func(*sqlite.Stmt)) ([]byte, error) {
+	_, blob, err := sqliteResult(conn, query, bind)
+	return blob, err
+}
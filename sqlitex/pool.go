@@ -19,8 +19,13 @@ package sqlitex
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 
 	"zombiezen.com/go/sqlite"
 )
@@ -43,18 +48,177 @@ type PoolOptions struct {
 	// PrepareConn is called for each connection in the pool to set up functions
 	// and other connection-specific state.
 	PrepareConn ConnPrepareFunc
+
+	// If SplitReadWrite is true, then the pool opens a single writer connection
+	// with Flags and PoolSize read-only connections
+	// (opened with [sqlite.OpenReadOnly] and the [query_only pragma]).
+	// Callers waiting in [Pool.TakeWriter] are served in FIFO order,
+	// so writers queue for the connection
+	// instead of contending for the database lock.
+	// This is most useful for databases in [WAL mode],
+	// where readers do not block the writer.
+	//
+	// [query_only pragma]: https://sqlite.org/pragma.html#pragma_query_only
+	// [WAL mode]: https://sqlite.org/wal.html
+	SplitReadWrite bool
+
+	// OnTake is called after a connection is taken from the pool
+	// with the connection and how long the caller waited for it.
+	// It must be safe to call from multiple goroutines.
+	OnTake func(conn *sqlite.Conn, wait time.Duration)
+
+	// OnPut is called before a connection is returned to the pool with [Pool.Put].
+	// It must be safe to call from multiple goroutines.
+	OnPut func(conn *sqlite.Conn)
+
+	// MaxConnLifetime is the maximum amount of time a connection may be reused.
+	// Expired connections are closed and replaced
+	// instead of being returned from [Pool.Take].
+	// If MaxConnLifetime is zero, connections are not closed due to their age.
+	MaxConnLifetime time.Duration
+
+	// MaxConnUses is the maximum number of times a connection may be taken
+	// from the pool before it is closed and replaced.
+	// If MaxConnUses is zero, connections are not closed due to their use count.
+	MaxConnUses int
+
+	// If LazyOpen is true, then the pool opens connections as they are needed
+	// instead of opening all of them in [NewPool].
+	// The pool will never have more than PoolSize connections open
+	// (plus the writer connection if SplitReadWrite is set).
+	LazyOpen bool
+
+	// MinIdle is the number of connections a LazyOpen pool keeps open and idle.
+	// [NewPool] opens MinIdle connections before returning
+	// and the pool opens more in the background as connections are taken.
+	// MinIdle is ignored if LazyOpen is false.
+	MinIdle int
+
+	// HealthCheck is called when a connection is taken from the pool,
+	// before PrepareConn.
+	// If HealthCheck returns an error,
+	// the connection is closed and replaced in the background
+	// and [Pool.Take] tries another connection.
+	// HealthCheck must be safe to call from multiple goroutines.
+	HealthCheck func(conn *sqlite.Conn) error
+
+	// If TrackTakes is true, then the pool records the caller's stack
+	// each time a connection is taken
+	// to help find code that does not return connections with [Pool.Put].
+	// [Pool.Close] reports each connection that is still taken
+	// when it is called.
+	// Recording stacks makes taking connections slower,
+	// so TrackTakes is intended for debugging.
+	TrackTakes bool
+
+	// If LeakThreshold is positive, then the pool reports connections
+	// that have been taken for longer than LeakThreshold.
+	// Setting LeakThreshold implies TrackTakes.
+	LeakThreshold time.Duration
+
+	// OnLeak is called when the pool reports a connection
+	// because of TrackTakes or LeakThreshold.
+	// If OnLeak is nil, the report is written with [log.Printf].
+	// It must be safe to call from multiple goroutines.
+	OnLeak func(OutstandingConn)
+}
+
+// OutstandingConn describes a connection that was taken from a [Pool]
+// and has not been returned.
+type OutstandingConn struct {
+	Conn *sqlite.Conn
+	// TakenAt is the time the connection was taken.
+	TakenAt time.Time
+	// Stack is the stack of the goroutine that took the connection,
+	// formatted with one function and file:line pair per frame.
+	Stack string
+}
+
+func (oc OutstandingConn) String() string {
+	return fmt.Sprintf("connection taken %v ago at:\n%s", time.Since(oc.TakenAt).Round(time.Millisecond), oc.Stack)
 }
 
 // Pool is a pool of SQLite connections.
 // It is safe for use by multiple goroutines concurrently.
+//
+// Connections that are returned to the pool with [Pool.Put]
+// while still in a transaction or with a statement still running
+// are closed and replaced in the background.
 type Pool struct {
-	free    chan *sqlite.Conn
-	closed  chan struct{}
-	prepare ConnPrepareFunc
+	uri         string
+	flags       sqlite.OpenFlags
+	size        int
+	split       bool
+	lazy        bool
+	minIdle     int
+	maxLifetime time.Duration
+	maxUses     int
+	prepare     ConnPrepareFunc
+	healthCheck func(*sqlite.Conn) error
+	onTake      func(*sqlite.Conn, time.Duration)
+	onPut       func(*sqlite.Conn)
+
+	trackTakes    bool
+	leakThreshold time.Duration
+	onLeak        func(OutstandingConn)
+
+	free   chan *sqlite.Conn
+	closed chan struct{}
+
+	// writerFree holds the single writer connection
+	// while it is not in use if the pool was created with SplitReadWrite.
+	writerFree chan *sqlite.Conn
+
+	// openers tracks connections being opened outside of NewPool.
+	openers sync.WaitGroup
+
+	mu      sync.Mutex
+	all     map[*sqlite.Conn]*poolConn
+	open    int // connections that are open or being opened, excluding the writer
+	pending int // connections being opened in the background, excluding the writer
+	waiting int // goroutines waiting on free
+	closing bool
+	stats   PoolStats
+}
+
+// poolConn is the pool's bookkeeping for a single connection.
+type poolConn struct {
+	cancel  context.CancelFunc
+	created time.Time
+	uses    int
+	writer  bool
+	inited  bool
+
+	// Set if the pool was created with TrackTakes while the connection is taken.
+	takenAt   time.Time
+	stack     string
+	leakTimer *time.Timer
+}
+
+// PoolStats is a snapshot of a [Pool]'s statistics.
+type PoolStats struct {
+	// OpenConnections is the number of open connections in the pool.
+	OpenConnections int
+	// InUse is the number of connections currently taken from the pool.
+	InUse int
+	// Idle is the number of open connections waiting to be taken.
+	Idle int
 
-	mu     sync.Mutex
-	all    map[*sqlite.Conn]context.CancelFunc
-	inited map[*sqlite.Conn]struct{}
+	// TakeCount is the total number of connections taken from the pool.
+	TakeCount int64
+	// WaitCount is the total number of takes
+	// that had to wait for a connection to be returned to the pool,
+	// including takes that are still waiting.
+	WaitCount int64
+	// WaitDuration is the total time spent waiting for connections.
+	WaitDuration time.Duration
+	// TimeoutCount is the total number of takes
+	// that failed because the context was done
+	// before a connection became available.
+	TimeoutCount int64
+	// ReplacedCount is the total number of connections
+	// that were closed because they were expired or unhealthy.
+	ReplacedCount int64
 }
 
 // Open opens a fixed-size pool of SQLite connections.
@@ -67,7 +231,7 @@ func Open(uri string, flags sqlite.OpenFlags, poolSize int) (pool *Pool, err err
 	})
 }
 
-// NewPool opens a fixed-size pool of SQLite connections.
+// NewPool opens a pool of SQLite connections.
 func NewPool(uri string, opts PoolOptions) (pool *Pool, err error) {
 	if uri == ":memory:" {
 		return nil, strerror{msg: `sqlite: ":memory:" does not work with multiple connections, use "file::memory:?mode=memory&cache=shared"`}
@@ -77,18 +241,6 @@ func NewPool(uri string, opts PoolOptions) (pool *Pool, err error) {
 	if poolSize < 1 {
 		poolSize = 10
 	}
-	p := &Pool{
-		free:    make(chan *sqlite.Conn, poolSize),
-		closed:  make(chan struct{}),
-		prepare: opts.PrepareConn,
-	}
-	defer func() {
-		// If an error occurred, call Close outside the lock so this doesn't deadlock.
-		if err != nil {
-			p.Close()
-		}
-	}()
-
 	flags := opts.Flags
 	if flags == 0 {
 		flags = sqlite.OpenReadWrite |
@@ -102,19 +254,98 @@ func NewPool(uri string, opts PoolOptions) (pool *Pool, err error) {
 	// const sqlitex_pool = sqlite.OpenFlags(0x01000000)
 	// flags |= sqlitex_pool
 
-	p.all = make(map[*sqlite.Conn]context.CancelFunc)
-	for i := 0; i < poolSize; i++ {
-		conn, err := sqlite.OpenConn(uri, flags)
+	p := &Pool{
+		uri:           uri,
+		flags:         flags,
+		size:          poolSize,
+		split:         opts.SplitReadWrite,
+		lazy:          opts.LazyOpen,
+		minIdle:       min(max(opts.MinIdle, 0), poolSize),
+		maxLifetime:   opts.MaxConnLifetime,
+		maxUses:       opts.MaxConnUses,
+		prepare:       opts.PrepareConn,
+		healthCheck:   opts.HealthCheck,
+		onTake:        opts.OnTake,
+		onPut:         opts.OnPut,
+		trackTakes:    opts.TrackTakes || opts.LeakThreshold > 0,
+		leakThreshold: opts.LeakThreshold,
+		onLeak:        opts.OnLeak,
+		free:          make(chan *sqlite.Conn, poolSize),
+		closed:        make(chan struct{}),
+		all:           make(map[*sqlite.Conn]*poolConn),
+	}
+	if p.onLeak == nil {
+		p.onLeak = func(oc OutstandingConn) {
+			log.Printf("sqlitex: pool %v", oc)
+		}
+	}
+	defer func() {
+		// If an error occurred, call Close outside the lock so this doesn't deadlock.
+		if err != nil {
+			p.Close()
+		}
+	}()
+
+	if p.split {
+		// Open the writer first so that it can create the database
+		// and set the journal mode before the readers open it.
+		p.writerFree = make(chan *sqlite.Conn, 1)
+		conn, err := p.openConn(true)
+		if err != nil {
+			return nil, err
+		}
+		p.register(conn, true)
+		p.writerFree <- conn
+	}
+	n := poolSize
+	if p.lazy {
+		n = p.minIdle
+	}
+	for i := 0; i < n; i++ {
+		conn, err := p.openConn(false)
 		if err != nil {
 			return nil, err
 		}
+		p.mu.Lock()
+		p.open++
+		p.mu.Unlock()
+		p.register(conn, false)
 		p.free <- conn
-		p.all[conn] = func() {}
 	}
 
 	return p, nil
 }
 
+// openConn opens a new connection for the pool.
+func (p *Pool) openConn(writer bool) (*sqlite.Conn, error) {
+	if !p.split || writer {
+		return sqlite.OpenConn(p.uri, p.flags)
+	}
+	flags := p.flags&^(sqlite.OpenReadWrite|sqlite.OpenCreate|sqlite.OpenWAL) | sqlite.OpenReadOnly
+	conn, err := sqlite.OpenConn(p.uri, flags)
+	if err != nil {
+		return nil, err
+	}
+	if err := ExecuteTransient(conn, "PRAGMA query_only = true;", nil); err != nil {
+		if closeErr := conn.Close(); closeErr != nil {
+			return nil, fmt.Errorf("%w (close: %v)", err, closeErr)
+		}
+		return nil, err
+	}
+	return conn, nil
+}
+
+// register adds a newly opened connection to p.all.
+func (p *Pool) register(conn *sqlite.Conn, writer bool) {
+	p.mu.Lock()
+	p.all[conn] = &poolConn{
+		cancel:  func() {},
+		created: time.Now(),
+		writer:  writer,
+	}
+	p.mu.Unlock()
+}
+
 // Get returns an SQLite connection from the Pool.
 //
 // Deprecated: Use [Pool.Take] instead.
@@ -141,40 +372,264 @@ func (p *Pool) Get(ctx context.Context) *sqlite.Conn {
 //
 // Applications must ensure that all non-nil Conns returned from Take
 // are returned to the same Pool with [Pool.Put].
+//
+// If the pool was created with [PoolOptions.SplitReadWrite],
+// Take is equivalent to [Pool.TakeWriter].
 func (p *Pool) Take(ctx context.Context) (*sqlite.Conn, error) {
-	select {
-	case conn := <-p.free:
-		ctx, cancel := context.WithCancel(ctx)
-		conn.SetInterrupt(ctx.Done())
+	return p.take(ctx, true)
+}
+
+// TakeWriter returns a connection from the Pool that can write to the database.
+// If the pool was created with [PoolOptions.SplitReadWrite],
+// TakeWriter returns the pool's single writer connection,
+// blocking until it is available.
+// Callers blocked in TakeWriter are served in the order they called TakeWriter.
+// Otherwise, TakeWriter is equivalent to [Pool.Take].
+//
+// Applications must ensure that all non-nil Conns returned from TakeWriter
+// are returned to the same Pool with [Pool.Put].
+func (p *Pool) TakeWriter(ctx context.Context) (*sqlite.Conn, error) {
+	return p.take(ctx, true)
+}
+
+// TakeReader returns a connection from the Pool that is only used for reading.
+// If the pool was created with [PoolOptions.SplitReadWrite],
+// TakeReader returns one of the pool's read-only connections.
+// Otherwise, TakeReader is equivalent to [Pool.Take].
+//
+// Applications must ensure that all non-nil Conns returned from TakeReader
+// are returned to the same Pool with [Pool.Put].
+func (p *Pool) TakeReader(ctx context.Context) (*sqlite.Conn, error) {
+	return p.take(ctx, false)
+}
+
+// take returns a healthy connection from the pool.
+// writer is ignored if the pool was not created with SplitReadWrite.
+func (p *Pool) take(ctx context.Context, writer bool) (*sqlite.Conn, error) {
+	writer = writer && p.split
+	var stack string
+	if p.trackTakes {
+		stack = callerStack()
+	}
+	var wait time.Duration
+	for {
+		conn, w, err := p.acquire(ctx, writer)
+		wait += w
+		if err != nil {
+			return nil, err
+		}
 
 		p.mu.Lock()
-		p.all[conn] = cancel
-		inited := true
-		if p.prepare != nil {
-			_, inited = p.inited[conn]
+		info := p.all[conn]
+		expired := p.expired(info)
+		p.mu.Unlock()
+		if expired {
+			p.discard(conn)
+			continue
 		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		conn.SetInterrupt(ctx.Done())
+		p.mu.Lock()
+		info.cancel = cancel
+		p.stats.InUse++
+		inited := info.inited || p.prepare == nil
 		p.mu.Unlock()
 
+		if p.healthCheck != nil {
+			if err := p.healthCheck(conn); err != nil {
+				if ctxErr := ctx.Err(); ctxErr != nil {
+					p.put(conn)
+					return nil, fmt.Errorf("get sqlite connection: %w", ctxErr)
+				}
+				p.release(conn)
+				p.discard(conn)
+				continue
+			}
+		}
 		if !inited {
 			if err := p.prepare(conn); err != nil {
 				p.put(conn)
 				return nil, fmt.Errorf("get sqlite connection: %w", err)
 			}
-
 			p.mu.Lock()
-			if p.inited == nil {
-				p.inited = make(map[*sqlite.Conn]struct{})
-			}
-			p.inited[conn] = struct{}{}
+			info.inited = true
 			p.mu.Unlock()
 		}
 
+		p.mu.Lock()
+		info.uses++
+		p.stats.TakeCount++
+		p.fill()
+		if p.trackTakes {
+			takenAt := time.Now()
+			info.takenAt = takenAt
+			info.stack = stack
+			if p.leakThreshold > 0 {
+				info.leakTimer = time.AfterFunc(p.leakThreshold, func() {
+					p.reportLeak(conn, takenAt)
+				})
+			}
+		}
+		p.mu.Unlock()
+		if p.onTake != nil {
+			p.onTake(conn, wait)
+		}
 		return conn, nil
+	}
+}
+
+// acquire receives an idle connection,
+// opening a new one if the pool is lazy and not yet full.
+// acquire records how long it had to wait in the pool's statistics.
+func (p *Pool) acquire(ctx context.Context, writer bool) (*sqlite.Conn, time.Duration, error) {
+	ch := p.free
+	if writer {
+		ch = p.writerFree
+	}
+	select {
+	case conn := <-ch:
+		return conn, 0, nil
+	default:
+	}
+
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		return nil, 0, fmt.Errorf("get sqlite connection: pool closed")
+	}
+	if !writer && p.open < p.size {
+		p.open++
+		p.openers.Add(1)
+		p.mu.Unlock()
+		conn, err := p.openNow()
+		if err != nil {
+			return nil, 0, fmt.Errorf("get sqlite connection: %w", err)
+		}
+		return conn, 0, nil
+	}
+	if !writer {
+		p.waiting++
+	}
+	p.stats.WaitCount++
+	p.mu.Unlock()
+
+	start := time.Now()
+	var conn *sqlite.Conn
+	var err error
+	timedOut := false
+	select {
+	case conn = <-ch:
 	case <-ctx.Done():
-		return nil, fmt.Errorf("get sqlite connection: %w", ctx.Err())
+		err = fmt.Errorf("get sqlite connection: %w", ctx.Err())
+		timedOut = true
 	case <-p.closed:
-		return nil, fmt.Errorf("get sqlite connection: pool closed")
+		err = fmt.Errorf("get sqlite connection: pool closed")
+	}
+	wait := time.Since(start)
+
+	p.mu.Lock()
+	if !writer {
+		p.waiting--
+	}
+	p.stats.WaitDuration += wait
+	if timedOut {
+		p.stats.TimeoutCount++
+	}
+	p.mu.Unlock()
+	return conn, wait, err
+}
+
+// openNow opens a reader connection for a slot already reserved in p.open.
+// The caller must have called p.openers.Add(1).
+func (p *Pool) openNow() (*sqlite.Conn, error) {
+	defer p.openers.Done()
+	conn, err := p.openConn(false)
+	if err != nil {
+		p.mu.Lock()
+		p.open--
+		p.mu.Unlock()
+		return nil, err
 	}
+	p.mu.Lock()
+	if p.closing {
+		p.open--
+		p.mu.Unlock()
+		closeConn(conn)
+		return nil, errors.New("pool closed")
+	}
+	p.mu.Unlock()
+	p.register(conn, false)
+	return conn, nil
+}
+
+// fill starts opening connections in the background
+// until a lazy pool has at least minIdle idle connections.
+// The caller must be holding p.mu.
+func (p *Pool) fill() {
+	if !p.lazy || p.closing {
+		return
+	}
+	for len(p.free)+p.pending < p.minIdle && p.open < p.size {
+		p.open++
+		p.pending++
+		p.openers.Add(1)
+		go p.replace(false)
+	}
+}
+
+// replace opens a connection in the background for a slot already reserved.
+// For readers, the caller must have incremented p.open and p.pending.
+// The caller must have called p.openers.Add(1).
+func (p *Pool) replace(writer bool) {
+	defer p.openers.Done()
+	delay := 10 * time.Millisecond
+	for {
+		conn, err := p.openConn(writer)
+		if err == nil {
+			p.mu.Lock()
+			if !writer {
+				p.pending--
+			}
+			if p.closing {
+				if !writer {
+					p.open--
+				}
+				p.mu.Unlock()
+				closeConn(conn)
+				return
+			}
+			p.mu.Unlock()
+			p.register(conn, writer)
+			if writer {
+				p.writerFree <- conn
+			} else {
+				p.free <- conn
+			}
+			return
+		}
+
+		select {
+		case <-time.After(delay):
+			delay = min(delay*2, 5*time.Second)
+		case <-p.closed:
+			if !writer {
+				p.mu.Lock()
+				p.pending--
+				p.open--
+				p.mu.Unlock()
+			}
+			return
+		}
+	}
+}
+
+// expired reports whether the connection has exceeded
+// the pool's maximum lifetime or number of uses.
+// The caller must be holding p.mu.
+func (p *Pool) expired(info *poolConn) bool {
+	return p.maxLifetime > 0 && time.Since(info.created) >= p.maxLifetime ||
+		p.maxUses > 0 && info.uses >= p.maxUses
 }
 
 // Put puts an SQLite connection back into the Pool.
@@ -195,14 +650,45 @@ func (p *Pool) Put(conn *sqlite.Conn) {
 			"connection returned to pool has active statement: %q",
 			query))
 	}
+	if p.onPut != nil {
+		p.onPut(conn)
+	}
 	p.put(conn)
 }
 
+// put returns a taken connection to the pool,
+// replacing it if it is no longer usable.
 func (p *Pool) put(conn *sqlite.Conn) {
+	p.release(conn)
 	p.mu.Lock()
-	cancel, found := p.all[conn]
+	expired := p.expired(p.all[conn])
+	p.mu.Unlock()
+	// release clears the interrupt,
+	// and SQLite ignores a pending interrupt once no statements are running,
+	// so only a statement that is still running makes the connection unusable.
+	if expired || !conn.AutocommitEnabled() || conn.HasBusyStmt() {
+		p.discard(conn)
+		return
+	}
+	p.send(conn)
+}
+
+// release clears the interrupt on a taken connection
+// and marks it as no longer in use.
+func (p *Pool) release(conn *sqlite.Conn) {
+	p.mu.Lock()
+	info, found := p.all[conn]
+	var cancel context.CancelFunc
 	if found {
-		p.all[conn] = func() {}
+		cancel = info.cancel
+		info.cancel = func() {}
+		p.stats.InUse--
+		if info.leakTimer != nil {
+			info.leakTimer.Stop()
+			info.leakTimer = nil
+		}
+		info.takenAt = time.Time{}
+		info.stack = ""
 	}
 	p.mu.Unlock()
 
@@ -212,35 +698,155 @@ func (p *Pool) put(conn *sqlite.Conn) {
 
 	conn.SetInterrupt(nil)
 	cancel()
-	p.free <- conn
+}
+
+// send makes an idle connection available to Take.
+func (p *Pool) send(conn *sqlite.Conn) {
+	p.mu.Lock()
+	writer := p.all[conn].writer
+	p.mu.Unlock()
+	if writer {
+		p.writerFree <- conn
+	} else {
+		p.free <- conn
+	}
+}
+
+// discard closes an idle connection and,
+// if the pool still needs it, opens a replacement in the background.
+// If the pool is closing, the connection is returned to the pool instead
+// so that [Pool.Close] can close it.
+func (p *Pool) discard(conn *sqlite.Conn) {
+	p.mu.Lock()
+	if p.closing {
+		p.mu.Unlock()
+		p.send(conn)
+		return
+	}
+	writer := p.all[conn].writer
+	delete(p.all, conn)
+	p.stats.ReplacedCount++
+	needed := writer || !p.lazy || p.waiting > 0 || p.open-1 < p.minIdle
+	if needed {
+		if !writer {
+			p.pending++
+		}
+		p.openers.Add(1)
+	} else {
+		p.open--
+	}
+	p.mu.Unlock()
+
+	closeConn(conn)
+	if needed {
+		go p.replace(writer)
+	}
+}
+
+// closeConn closes a connection that the pool no longer tracks.
+// There is no caller to return an error to,
+// so an error is written with [log.Printf].
+func closeConn(conn *sqlite.Conn) {
+	if err := conn.Close(); err != nil {
+		log.Printf("sqlitex: pool: %v", err)
+	}
 }
 
 // Close interrupts and closes all the connections in the Pool,
 // blocking until all connections are returned to the Pool.
 func (p *Pool) Close() (err error) {
+	p.mu.Lock()
+	p.closing = true
+	p.mu.Unlock()
 	close(p.closed)
+	p.openers.Wait()
 
 	p.mu.Lock()
 	n := len(p.all)
 	cancelList := make([]context.CancelFunc, 0, n)
-	for conn, cancel := range p.all {
-		cancelList = append(cancelList, cancel)
-		p.all[conn] = func() {}
+	var outstanding []OutstandingConn
+	for conn, info := range p.all {
+		cancelList = append(cancelList, info.cancel)
+		info.cancel = func() {}
+		if !info.takenAt.IsZero() {
+			outstanding = append(outstanding, OutstandingConn{
+				Conn:    conn,
+				TakenAt: info.takenAt,
+				Stack:   info.stack,
+			})
+		}
 	}
 	p.mu.Unlock()
 
+	for _, oc := range outstanding {
+		p.onLeak(oc)
+	}
 	for _, cancel := range cancelList {
 		cancel()
 	}
 	for closed := 0; closed < n; closed++ {
-		conn := <-p.free
+		var conn *sqlite.Conn
+		select {
+		case conn = <-p.free:
+		case conn = <-p.writerFree:
+		}
 		if err2 := conn.Close(); err == nil {
 			err = err2
 		}
+		p.mu.Lock()
+		delete(p.all, conn)
+		p.mu.Unlock()
 	}
 	return
 }
 
+// reportLeak calls p.onLeak if conn is still taken
+// from the Take that happened at takenAt.
+func (p *Pool) reportLeak(conn *sqlite.Conn, takenAt time.Time) {
+	p.mu.Lock()
+	info := p.all[conn]
+	if info == nil || !info.takenAt.Equal(takenAt) {
+		p.mu.Unlock()
+		return
+	}
+	oc := OutstandingConn{
+		Conn:    conn,
+		TakenAt: info.takenAt,
+		Stack:   info.stack,
+	}
+	p.mu.Unlock()
+	p.onLeak(oc)
+}
+
+// callerStack returns the stack of the function
+// that called the [Pool] method calling callerStack.
+func callerStack() string {
+	var pc [32]uintptr
+	n := runtime.Callers(2, pc[:])
+	frames := runtime.CallersFrames(pc[:n])
+	sb := new(strings.Builder)
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "zombiezen.com/go/sqlite/sqlitex.(*Pool).") {
+			fmt.Fprintf(sb, "%s\n\t%s:%d\n", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Stats returns the pool's current statistics.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.OpenConnections = len(p.all)
+	stats.Idle = stats.OpenConnections - stats.InUse
+	return stats
+}
+
 // A ConnPrepareFunc is called for each connection in a pool
 // to set up connection-specific state.
 // It must be safe to call from multiple goroutines.
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -323,3 +324,429 @@ func TestPoolPrepareConn(t *testing.T) {
 		t.Error("foreign_keys not enabled")
 	}
 }
+
+func TestPoolSplitReadWrite(t *testing.T) {
+	ctx := context.Background()
+	dbName := filepath.Join(t.TempDir(), "split.db")
+	pool, err := sqlitex.NewPool(dbName, sqlitex.PoolOptions{
+		PoolSize:       2,
+		SplitReadWrite: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+
+	writer, err := pool.TakeWriter(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = sqlitex.ExecuteScript(writer, `
+		CREATE TABLE foo (x INTEGER);
+		INSERT INTO foo VALUES (1), (2), (3);
+	`, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Only one writer may be taken at a time.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	if conn, err := pool.TakeWriter(timeoutCtx); err == nil {
+		t.Error("TakeWriter succeeded while writer was taken")
+		pool.Put(conn)
+	}
+	cancel()
+	pool.Put(writer)
+
+	reader, err := pool.TakeReader(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(reader)
+	if reader == writer {
+		t.Error("TakeReader returned the writer connection")
+	}
+	sum, err := sqlitex.ResultInt(reader.Prep("SELECT sum(x) FROM foo;"))
+	if err != nil {
+		t.Error(err)
+	} else if sum != 6 {
+		t.Errorf("sum(x) = %d; want 6", sum)
+	}
+	err = sqlitex.ExecuteTransient(reader, "INSERT INTO foo VALUES (4);", nil)
+	if got, want := sqlite.ErrCode(err), sqlite.ResultReadOnly; got != want {
+		t.Errorf("INSERT on reader error code = %v; want %v", got, want)
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var taken, put int
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "stats.db"), sqlitex.PoolOptions{
+		PoolSize: 2,
+		OnTake: func(conn *sqlite.Conn, wait time.Duration) {
+			mu.Lock()
+			taken++
+			mu.Unlock()
+		},
+		OnPut: func(conn *sqlite.Conn) {
+			mu.Lock()
+			put++
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+
+	conn1, err := pool.Take(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn2, err := pool.Take(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := pool.Stats()
+	if stats.OpenConnections != 2 || stats.InUse != 2 || stats.Idle != 0 {
+		t.Errorf("Stats() = %+v; want 2 open, 2 in use, 0 idle", stats)
+	}
+
+	// Pool is exhausted: the next take should time out.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	if conn, err := pool.Take(timeoutCtx); err == nil {
+		t.Error("Take succeeded on exhausted pool")
+		pool.Put(conn)
+	}
+	cancel()
+
+	// A waiting take should be recorded once a connection is returned.
+	done := make(chan *sqlite.Conn)
+	go func() {
+		conn, err := pool.Take(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- conn
+	}()
+	for pool.Stats().WaitCount < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	pool.Put(conn1)
+	conn3 := <-done
+	pool.Put(conn2)
+	pool.Put(conn3)
+
+	stats = pool.Stats()
+	want := sqlitex.PoolStats{
+		OpenConnections: 2,
+		InUse:           0,
+		Idle:            2,
+		TakeCount:       3,
+		WaitCount:       2,
+		TimeoutCount:    1,
+	}
+	if stats.WaitDuration < 10*time.Millisecond {
+		t.Errorf("Stats().WaitDuration = %v; want >=10ms", stats.WaitDuration)
+	}
+	stats.WaitDuration = 0
+	if stats != want {
+		t.Errorf("Stats() = %+v; want %+v", stats, want)
+	}
+	mu.Lock()
+	if taken != 3 || put != 3 {
+		t.Errorf("OnTake called %d times, OnPut called %d times; want 3, 3", taken, put)
+	}
+	mu.Unlock()
+}
+
+func TestPoolReplace(t *testing.T) {
+	tests := []struct {
+		name string
+		opts sqlitex.PoolOptions
+		use  func(t *testing.T, conn *sqlite.Conn)
+	}{
+		{
+			name: "MaxConnUses",
+			opts: sqlitex.PoolOptions{MaxConnUses: 1},
+			use:  func(t *testing.T, conn *sqlite.Conn) {},
+		},
+		{
+			name: "MaxConnLifetime",
+			opts: sqlitex.PoolOptions{MaxConnLifetime: time.Millisecond},
+			use: func(t *testing.T, conn *sqlite.Conn) {
+				time.Sleep(2 * time.Millisecond)
+			},
+		},
+		{
+			name: "InTransaction",
+			use: func(t *testing.T, conn *sqlite.Conn) {
+				if err := sqlitex.ExecuteTransient(conn, "BEGIN;", nil); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "BusyStmt",
+			use: func(t *testing.T, conn *sqlite.Conn) {
+				// The statement is deliberately left unfinalized.
+				stmt, _, err := conn.PrepareTransient("SELECT count(*) FROM sqlite_schema UNION ALL SELECT 2;")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := stmt.Step(); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ctx := context.Background()
+			opts := test.opts
+			opts.PoolSize = 1
+			pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "replace.db"), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer func() {
+				if err := pool.Close(); err != nil {
+					t.Error("Close:", err)
+				}
+			}()
+
+			conn1, err := pool.Take(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.use(t, conn1)
+			pool.Put(conn1)
+			conn2, err := pool.Take(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer pool.Put(conn2)
+			if conn1 == conn2 {
+				t.Error("Take returned the same connection")
+			}
+			if got := pool.Stats().ReplacedCount; got != 1 {
+				t.Errorf("Stats().ReplacedCount = %d; want 1", got)
+			}
+
+			// A checkpoint cannot copy pages into the database
+			// while another connection is reading it,
+			// so this only checkpoints every frame
+			// if the replaced connection was closed.
+			if err := sqlitex.ExecuteTransient(conn2, "CREATE TABLE t (x);", nil); err != nil {
+				t.Fatal(err)
+			}
+			var logFrames, checkpointed int
+			err = sqlitex.ExecuteTransient(conn2, "PRAGMA wal_checkpoint;", &sqlitex.ExecOptions{
+				ResultFunc: func(stmt *sqlite.Stmt) error {
+					logFrames = stmt.ColumnInt(1)
+					checkpointed = stmt.ColumnInt(2)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if checkpointed != logFrames {
+				t.Errorf("checkpointed %d of %d frames; want all (replaced connection still open?)", checkpointed, logFrames)
+			}
+		})
+	}
+}
+
+func TestPoolPutCanceled(t *testing.T) {
+	var mu sync.Mutex
+	prepared := 0
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "canceled.db"), sqlitex.PoolOptions{
+		PoolSize: 1,
+		PrepareConn: func(conn *sqlite.Conn) error {
+			mu.Lock()
+			prepared++
+			mu.Unlock()
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	conn1, err := pool.Take(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sqlitex.ExecuteTransient(conn1, "SELECT 1;", nil); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	for !conn1.IsInterrupted() {
+		time.Sleep(time.Millisecond)
+	}
+	pool.Put(conn1)
+
+	// An idle connection whose context was canceled is still usable.
+	conn2, err := pool.Take(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(conn2)
+	if conn1 != conn2 {
+		t.Error("Take returned a different connection")
+	}
+	if err := sqlitex.ExecuteTransient(conn2, "SELECT 1;", nil); err != nil {
+		t.Error(err)
+	}
+	if got := pool.Stats().ReplacedCount; got != 0 {
+		t.Errorf("Stats().ReplacedCount = %d; want 0", got)
+	}
+	mu.Lock()
+	if prepared != 1 {
+		t.Errorf("PrepareConn called %d times; want 1", prepared)
+	}
+	mu.Unlock()
+}
+
+func TestPoolHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	var bad *sqlite.Conn
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "health.db"), sqlitex.PoolOptions{
+		PoolSize: 1,
+		HealthCheck: func(conn *sqlite.Conn) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if bad == nil {
+				bad = conn
+			}
+			if conn == bad {
+				return errors.New("bad connection")
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+
+	conn, err := pool.Take(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Put(conn)
+	mu.Lock()
+	if conn == bad {
+		t.Error("Take returned connection that failed health check")
+	}
+	mu.Unlock()
+	if got := pool.Stats().ReplacedCount; got != 1 {
+		t.Errorf("Stats().ReplacedCount = %d; want 1", got)
+	}
+}
+
+func TestPoolLazyOpen(t *testing.T) {
+	ctx := context.Background()
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "lazy.db"), sqlitex.PoolOptions{
+		PoolSize: 3,
+		LazyOpen: true,
+		MinIdle:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+	if got := pool.Stats().OpenConnections; got != 1 {
+		t.Errorf("after NewPool, Stats().OpenConnections = %d; want 1", got)
+	}
+
+	var conns []*sqlite.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := pool.Take(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		conns = append(conns, conn)
+	}
+	stats := pool.Stats()
+	if stats.OpenConnections != 3 || stats.InUse != 3 {
+		t.Errorf("after taking 3 connections, Stats() = %+v; want 3 open and in use", stats)
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	if conn, err := pool.Take(timeoutCtx); err == nil {
+		t.Error("Take succeeded beyond PoolSize")
+		pool.Put(conn)
+	}
+	cancel()
+	for _, conn := range conns {
+		pool.Put(conn)
+	}
+}
+
+func TestPoolLeakThreshold(t *testing.T) {
+	ctx := context.Background()
+	leaks := make(chan sqlitex.OutstandingConn, 2)
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "leak.db"), sqlitex.PoolOptions{
+		PoolSize:      1,
+		LeakThreshold: 10 * time.Millisecond,
+		OnLeak: func(oc sqlitex.OutstandingConn) {
+			leaks <- oc
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := pool.Take(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oc := <-leaks
+	if oc.Conn != conn {
+		t.Error("leak reported for wrong connection")
+	}
+	if !strings.Contains(oc.Stack, "TestPoolLeakThreshold") {
+		t.Errorf("leak stack does not mention test function:\n%s", oc.Stack)
+	}
+	if strings.Contains(oc.Stack, "(*Pool)") {
+		t.Errorf("leak stack includes Pool methods:\n%s", oc.Stack)
+	}
+
+	closeDone := make(chan error)
+	go func() {
+		closeDone <- pool.Close()
+	}()
+	oc = <-leaks
+	if oc.Conn != conn {
+		t.Error("Close reported wrong connection")
+	}
+	pool.Put(conn)
+	if err := <-closeDone; err != nil {
+		t.Error("Close:", err)
+	}
+}
@@ -5,6 +5,7 @@ package sqlitex
 
 import (
 	"errors"
+	"reflect"
 
 	"zombiezen.com/go/sqlite"
 )
@@ -117,3 +118,90 @@ func ResultBytes(stmt *sqlite.Stmt, buf []byte) (int, error) {
 	}
 	return read, nil
 }
+
+// Query executes an SQLite query with [Execute]
+// and returns its result rows converted to values of type T.
+// opts.ResultFunc is ignored.
+//
+// If T is a struct (or a pointer to a struct),
+// each result column is stored in the field
+// whose `sqlite:"name"` tag matches the column name.
+// Fields without a tag are matched by their name,
+// preferring an exact match over a case-insensitive one.
+// Fields with a tag of "-" and unexported fields are ignored,
+// and fields of embedded structs are matched as if they were fields of T.
+// Columns that do not match any field are ignored.
+// Otherwise, T is read from the first result column.
+//
+// Values are converted with the [sqlite.Stmt] Column* methods:
+//
+//   - integers    from ColumnInt64, failing if the value overflows
+//   - floats      from ColumnFloat
+//   - []byte      from ColumnBytes, or nil for NULL
+//   - string      from ColumnText
+//   - bool        from ColumnBool
+//   - [time.Time] from ColumnTime, or the zero time for NULL
+//   - any         from the column's type: int64, float64, string, []byte, or nil
+//
+// Pointers are set to nil for NULL
+// and point to the converted value otherwise.
+// If a pointer to the field's type implements [sqlite.Scanner],
+// its ScanColumn method is called instead.
+// Otherwise, if it implements [encoding.TextUnmarshaler],
+// its UnmarshalText method is called with the column's text,
+// or the field is set to its zero value for NULL.
+// Other types return an error.
+func Query[T any](conn *sqlite.Conn, query string, opts *ExecOptions) ([]T, error) {
+	var results []T
+	var rs *rowScanner
+	err := Execute(conn, query, withResultFunc(opts, func(stmt *sqlite.Stmt) error {
+		if rs == nil {
+			rs = newRowScanner(reflect.TypeFor[T](), stmt)
+		}
+		var x T
+		if err := rs.scan(stmt, reflect.ValueOf(&x).Elem()); err != nil {
+			return err
+		}
+		results = append(results, x)
+		return nil
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// QueryOne executes an SQLite query with [Execute]
+// and returns its first and only result row converted to a value of type T
+// as described in [Query].
+// It returns an error if there is not exactly one result row.
+// opts.ResultFunc is ignored.
+func QueryOne[T any](conn *sqlite.Conn, query string, opts *ExecOptions) (T, error) {
+	var result T
+	found := false
+	err := Execute(conn, query, withResultFunc(opts, func(stmt *sqlite.Stmt) error {
+		if found {
+			return errMultipleResults
+		}
+		found = true
+		return newRowScanner(reflect.TypeFor[T](), stmt).scan(stmt, reflect.ValueOf(&result).Elem())
+	}))
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if !found {
+		return result, errNoResults
+	}
+	return result, nil
+}
+
+// withResultFunc returns a copy of opts with ResultFunc set to f.
+func withResultFunc(opts *ExecOptions, f func(stmt *sqlite.Stmt) error) *ExecOptions {
+	newOpts := new(ExecOptions)
+	if opts != nil {
+		*newOpts = *opts
+	}
+	newOpts.ResultFunc = f
+	return newOpts
+}
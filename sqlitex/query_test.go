@@ -4,8 +4,11 @@
 package sqlitex
 
 import (
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
 	"zombiezen.com/go/sqlite"
 )
 
@@ -358,3 +361,157 @@ INSERT INTO foo VALUES (1, CAST('hi' AS BLOB)), (2, CAST('bye' AS BLOB));`, nil)
 		}
 	})
 }
+
+type upperText string
+
+func (u *upperText) UnmarshalText(text []byte) error {
+	*u = upperText(strings.ToUpper(string(text)))
+	return nil
+}
+
+type typeScanner string
+
+func (ts *typeScanner) ScanColumn(stmt *sqlite.Stmt, col int) error {
+	*ts = typeScanner(stmt.ColumnType(col).String())
+	return nil
+}
+
+type queryTimestamps struct {
+	Created int64 `sqlite:"created_at"`
+}
+
+type queryRow struct {
+	queryTimestamps
+	ID       int64
+	Name     string `sqlite:"full_name"`
+	Nickname *string
+	Shout    upperText
+	Kind     typeScanner `sqlite:"data"`
+	Data     []byte      `sqlite:"-"`
+	ignored  int
+}
+
+func TestQuery(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = ExecuteScript(conn, `
+		CREATE TABLE people (
+			id integer primary key,
+			full_name text not null,
+			nickname text,
+			shout text,
+			data blob,
+			created_at integer
+		);
+		INSERT INTO people VALUES
+			(1, 'Alice Smith', NULL, 'hi', x'00', 100),
+			(2, 'Bob Jones', 'Bobby', 'hey', NULL, 200);
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Struct", func(t *testing.T) {
+		got, err := Query[queryRow](conn, `SELECT * FROM people ORDER BY id;`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobby := "Bobby"
+		want := []queryRow{
+			{
+				queryTimestamps: queryTimestamps{Created: 100},
+				ID:              1,
+				Name:            "Alice Smith",
+				Shout:           "HI",
+				Kind:            "SQLITE_BLOB",
+			},
+			{
+				queryTimestamps: queryTimestamps{Created: 200},
+				ID:              2,
+				Name:            "Bob Jones",
+				Nickname:        &bobby,
+				Shout:           "HEY",
+				Kind:            "SQLITE_NULL",
+			},
+		}
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(queryRow{})); diff != "" {
+			t.Errorf("Query(...) (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Pointer", func(t *testing.T) {
+		got, err := Query[*queryRow](conn, `SELECT id FROM people ORDER BY id;`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].ID != 1 || got[1].ID != 2 {
+			t.Errorf("Query(...) = %+v; want IDs 1 and 2", got)
+		}
+	})
+
+	t.Run("Column", func(t *testing.T) {
+		got, err := Query[*string](conn, `SELECT nickname FROM people ORDER BY id;`, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bobby := "Bobby"
+		want := []*string{nil, &bobby}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Query(...) (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("One", func(t *testing.T) {
+		got, err := QueryOne[queryRow](conn, `SELECT id, full_name FROM people WHERE id = :id;`, &ExecOptions{
+			Named: map[string]any{":id": 2},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.ID != 2 || got.Name != "Bob Jones" {
+			t.Errorf("QueryOne(...) = %+v; want ID 2, Name Bob Jones", got)
+		}
+	})
+
+	t.Run("OneMultiple", func(t *testing.T) {
+		if _, err := QueryOne[int](conn, `SELECT id FROM people;`, nil); err == nil {
+			t.Error("QueryOne(...) did not return an error")
+		}
+	})
+
+	t.Run("OneNoRows", func(t *testing.T) {
+		if _, err := QueryOne[int](conn, `SELECT id FROM people WHERE id = 3;`, nil); err == nil {
+			t.Error("QueryOne(...) did not return an error")
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		want := time.Date(2024, time.March, 4, 5, 6, 7, 0, time.UTC)
+		type row struct {
+			Day  string
+			Time time.Time
+		}
+		got, err := QueryOne[row](conn, `SELECT date(:t) AS day, :t AS time;`, &ExecOptions{
+			Named: map[string]any{":t": want},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Day != "2024-03-04" || !got.Time.Equal(want) {
+			t.Errorf("QueryOne(...) = %+v; want {Day:2024-03-04 Time:%v}", got, want)
+		}
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		if _, err := Query[int8](conn, `SELECT 1000;`, nil); err == nil {
+			t.Error("Query(...) did not return an error")
+		}
+	})
+}
@@ -0,0 +1,266 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+var (
+	scannerType         = reflect.TypeFor[sqlite.Scanner]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	timeType            = reflect.TypeFor[time.Time]()
+)
+
+// structField is a field in a (possibly embedded) struct
+// that can be mapped to a column or parameter.
+type structField struct {
+	name  string
+	index []int
+}
+
+// structFields returns the fields of the struct type t
+// in the order they are declared.
+// A field's name is taken from its `sqlite:"name"` tag if present,
+// or the field's name otherwise.
+// Fields with a tag of "-" and unexported fields are skipped.
+// Fields of embedded structs without a tag are included
+// as if they were fields of t.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	var visit func(t reflect.Type, index []int)
+	visit = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag, hasTag := f.Tag.Lookup("sqlite")
+			if tag == "-" {
+				continue
+			}
+			fieldIndex := append(index[:len(index):len(index)], i)
+			if f.Anonymous && !hasTag {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct && !isColumnType(ft) {
+					// Unexported embedded struct pointers can't be allocated.
+					if f.Type.Kind() != reflect.Pointer || f.IsExported() {
+						visit(ft, fieldIndex)
+					}
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name := tag
+			if name == "" {
+				name = f.Name
+			}
+			fields = append(fields, structField{name: name, index: fieldIndex})
+		}
+	}
+	visit(t, nil)
+	return fields
+}
+
+// isColumnType reports whether values of type t
+// are read from a single column
+// instead of having their fields mapped to columns.
+func isColumnType(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return true
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(scannerType) || pt.Implements(textUnmarshalerType)
+}
+
+// rowScanner maps the columns of a statement to a Go type.
+type rowScanner struct {
+	typ reflect.Type
+	// cols[i] is the field index for column i
+	// or nil if the column is not mapped to a field.
+	// cols is nil if typ is a column type.
+	cols [][]int
+}
+
+func newRowScanner(t reflect.Type, stmt *sqlite.Stmt) *rowScanner {
+	rs := &rowScanner{typ: t}
+	if isColumnType(t) {
+		return rs
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	fields := structFields(t)
+	n := stmt.ColumnCount()
+	rs.cols = make([][]int, n)
+	for col := 0; col < n; col++ {
+		rs.cols[col] = findField(fields, stmt.ColumnName(col))
+	}
+	return rs
+}
+
+// findField returns the index of the field with the given name,
+// preferring an exact match over a case-insensitive one.
+// findField returns nil if no field matches.
+func findField(fields []structField, name string) []int {
+	var match []int
+	for _, f := range fields {
+		if f.name == name {
+			return f.index
+		}
+		if match == nil && strings.EqualFold(f.name, name) {
+			match = f.index
+		}
+	}
+	return match
+}
+
+// scan reads the current row of stmt into v,
+// which must be an addressable value of rs.typ.
+func (rs *rowScanner) scan(stmt *sqlite.Stmt, v reflect.Value) error {
+	if rs.cols == nil {
+		if stmt.ColumnCount() == 0 {
+			return fmt.Errorf("sqlitex: scan into %v: statement has no columns", rs.typ)
+		}
+		return scanColumn(stmt, 0, v)
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	for col, index := range rs.cols {
+		if index == nil {
+			continue
+		}
+		if err := scanColumn(stmt, col, fieldByIndexAlloc(v, index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldByIndexAlloc returns the nested field of the struct v
+// corresponding to index,
+// allocating any nil embedded struct pointers along the way.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// scanColumn reads the given column of the current row of stmt into v,
+// which must be addressable.
+func scanColumn(stmt *sqlite.Stmt, col int, v reflect.Value) error {
+	if v.CanAddr() {
+		switch x := v.Addr().Interface().(type) {
+		case sqlite.Scanner:
+			if err := x.ScanColumn(stmt, col); err != nil {
+				return fmt.Errorf("sqlitex: scan column %q: %w", stmt.ColumnName(col), err)
+			}
+			return nil
+		case *time.Time:
+			t, err := stmt.ColumnTime(col)
+			if err != nil {
+				return fmt.Errorf("sqlitex: scan column %q: %w", stmt.ColumnName(col), err)
+			}
+			*x = t
+			return nil
+		case encoding.TextUnmarshaler:
+			if stmt.ColumnIsNull(col) {
+				v.SetZero()
+				return nil
+			}
+			if err := x.UnmarshalText([]byte(stmt.ColumnText(col))); err != nil {
+				return fmt.Errorf("sqlitex: scan column %q: %w", stmt.ColumnName(col), err)
+			}
+			return nil
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if stmt.ColumnIsNull(col) {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return scanColumn(stmt, col, v.Elem())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := stmt.ColumnInt64(col)
+		if v.OverflowInt(i) {
+			return fmt.Errorf("sqlitex: scan column %q: %d overflows %v", stmt.ColumnName(col), i, v.Type())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i := stmt.ColumnInt64(col)
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return fmt.Errorf("sqlitex: scan column %q: %d overflows %v", stmt.ColumnName(col), i, v.Type())
+		}
+		v.SetUint(uint64(i))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(stmt.ColumnFloat(col))
+	case reflect.String:
+		v.SetString(stmt.ColumnText(col))
+	case reflect.Bool:
+		v.SetBool(stmt.ColumnBool(col))
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("sqlitex: scan column %q: unsupported type %v", stmt.ColumnName(col), v.Type())
+		}
+		if stmt.ColumnIsNull(col) {
+			v.SetZero()
+			return nil
+		}
+		buf := make([]byte, stmt.ColumnLen(col))
+		stmt.ColumnBytes(col, buf)
+		v.SetBytes(buf)
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			return fmt.Errorf("sqlitex: scan column %q: unsupported type %v", stmt.ColumnName(col), v.Type())
+		}
+		var x any
+		switch stmt.ColumnType(col) {
+		case sqlite.TypeInteger:
+			x = stmt.ColumnInt64(col)
+		case sqlite.TypeFloat:
+			x = stmt.ColumnFloat(col)
+		case sqlite.TypeText:
+			x = stmt.ColumnText(col)
+		case sqlite.TypeBlob:
+			buf := make([]byte, stmt.ColumnLen(col))
+			stmt.ColumnBytes(col, buf)
+			x = buf
+		}
+		if x == nil {
+			v.SetZero()
+		} else {
+			v.Set(reflect.ValueOf(x))
+		}
+	default:
+		return fmt.Errorf("sqlitex: scan column %q: unsupported type %v", stmt.ColumnName(col), v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"fmt"
+	"iter"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// Rows returns an iterator over the result rows of an SQLite query.
+// Arguments are bound from opts as in [Execute] and opts.ResultFunc is ignored.
+// The query runs each time the iterator is used.
+//
+// The statement is yielded for each result row
+// and can be read with its Column* methods until the next iteration.
+// If an error occurs, it is yielded with a nil statement
+// and iteration stops.
+// The statement is reset when iteration stops,
+// even if the loop exits early.
+//
+// As Rows is implemented using [sqlite.Conn.Prepare],
+// subsequent calls to Rows with the same statement
+// will reuse the cached statement object.
+// As such, the query must not be used on the same connection
+// until iteration stops.
+func Rows(conn *sqlite.Conn, query string, opts *ExecOptions) iter.Seq2[*sqlite.Stmt, error] {
+	return func(yield func(*sqlite.Stmt, error) bool) {
+		stmt, err := conn.Prepare(query)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer stmt.Reset()
+		if !rows(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts, yield) {
+			return
+		}
+		if err := stmt.Reset(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// RowsTransient returns an iterator over the result rows of an SQLite query
+// like [Rows], but without caching the underlying statement.
+// The statement is finalized when iteration stops,
+// even if the loop exits early.
+func RowsTransient(conn *sqlite.Conn, query string, opts *ExecOptions) iter.Seq2[*sqlite.Stmt, error] {
+	return func(yield func(*sqlite.Stmt, error) bool) {
+		stmt, trailingBytes, err := conn.PrepareTransient(query)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer stmt.Finalize()
+		if trailingBytes != 0 {
+			yield(nil, fmt.Errorf("sqlitex: execute: query %q has trailing bytes", query))
+			return
+		}
+		rows(stmt, forbidMissing|forbidExtra|forbidUnknownTypes, opts, yield)
+	}
+}
+
+// rows binds the arguments in opts to stmt
+// and yields each of its result rows.
+// It reports whether the statement ran to completion
+// without errors or yield returning false.
+func rows(stmt *sqlite.Stmt, flags uint8, opts *ExecOptions, yield func(*sqlite.Stmt, error) bool) bool {
+	if err := bindArgs(stmt, flags, opts); err != nil {
+		yield(nil, err)
+		return false
+	}
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			yield(nil, err)
+			return false
+		}
+		if !hasRow {
+			return true
+		}
+		if !yield(stmt, nil) {
+			return false
+		}
+	}
+}
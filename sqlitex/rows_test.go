@@ -0,0 +1,81 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"slices"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+)
+
+func TestRows(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = ExecuteScript(conn, `
+		CREATE TABLE foo (x INTEGER);
+		INSERT INTO foo VALUES (1), (2), (3), (4);
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const query = `SELECT x FROM foo WHERE x > :min ORDER BY x;`
+	opts := &ExecOptions{Named: map[string]any{":min": 1}}
+	tests := []struct {
+		name string
+		rows func(conn *sqlite.Conn, query string, opts *ExecOptions) func(yield func(*sqlite.Stmt, error) bool)
+	}{
+		{"Rows", func(conn *sqlite.Conn, query string, opts *ExecOptions) func(yield func(*sqlite.Stmt, error) bool) {
+			return Rows(conn, query, opts)
+		}},
+		{"RowsTransient", func(conn *sqlite.Conn, query string, opts *ExecOptions) func(yield func(*sqlite.Stmt, error) bool) {
+			return RowsTransient(conn, query, opts)
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got []int
+			for stmt, err := range test.rows(conn, query, opts) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, stmt.ColumnInt(0))
+			}
+			if want := []int{2, 3, 4}; !slices.Equal(got, want) {
+				t.Errorf("rows = %v; want %v", got, want)
+			}
+
+			got = nil
+			for stmt, err := range test.rows(conn, query, opts) {
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, stmt.ColumnInt(0))
+				break
+			}
+			if want := []int{2}; !slices.Equal(got, want) {
+				t.Errorf("rows with break = %v; want %v", got, want)
+			}
+			if query := conn.CheckReset(); query != "" {
+				t.Errorf("statement %q not reset after break", query)
+			}
+
+			var gotErr error
+			for _, err := range test.rows(conn, "SELECT :missing;", nil) {
+				gotErr = err
+			}
+			if gotErr == nil {
+				t.Error("missing argument did not yield an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+// TxMode is the locking behavior of a transaction.
+// See https://www.sqlite.org/lang_transaction.html for details.
+type TxMode int
+
+// Transaction modes.
+const (
+	// TxDeferred starts a DEFERRED transaction,
+	// which does not acquire locks until the database is first read or written.
+	TxDeferred TxMode = iota
+	// TxImmediate starts an IMMEDIATE transaction,
+	// which starts a write transaction right away.
+	TxImmediate
+	// TxExclusive starts an EXCLUSIVE transaction.
+	TxExclusive
+)
+
+// String returns the SQL keyword for the mode.
+func (mode TxMode) String() string {
+	switch mode {
+	case TxDeferred:
+		return "DEFERRED"
+	case TxImmediate:
+		return "IMMEDIATE"
+	case TxExclusive:
+		return "EXCLUSIVE"
+	default:
+		return fmt.Sprintf("TxMode(%d)", int(mode))
+	}
+}
+
+// TxOptions is the set of optional arguments for [Pool.Transact].
+type TxOptions struct {
+	// Mode is the kind of transaction to start.
+	Mode TxMode
+
+	// MaxRetries is the maximum number of times
+	// a transaction that fails with a retryable error is retried
+	// before the error is returned.
+	// Errors are retryable if their code is
+	// [sqlite.ResultBusy], [sqlite.ResultLocked],
+	// or one of their extended codes,
+	// or [sqlite.ResultErrorSnapshot].
+	// If MaxRetries is zero, [DefaultMaxRetries] is used.
+	// If MaxRetries is negative, such errors are returned immediately.
+	// To retry until the context is done, use [UnlimitedRetries].
+	MaxRetries int
+	// RetryDelay is the duration to wait before the first retry.
+	// The delay doubles after each retry up to MaxRetryDelay.
+	// If RetryDelay is zero, then 10 milliseconds is used.
+	RetryDelay time.Duration
+	// MaxRetryDelay is the longest duration to wait between retries.
+	// If MaxRetryDelay is zero, then 1 second is used.
+	MaxRetryDelay time.Duration
+}
+
+// Retry limits for [TxOptions.MaxRetries] and [BackupOptions.MaxRetries].
+const (
+	// DefaultMaxRetries is the number of retries used
+	// when MaxRetries is zero.
+	DefaultMaxRetries = 10
+	// UnlimitedRetries retries until the context is done.
+	UnlimitedRetries = math.MaxInt
+)
+
+// maxRetries returns the retry limit for a MaxRetries option.
+func maxRetries(n int) int {
+	switch {
+	case n == 0:
+		return DefaultMaxRetries
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
+
+// Do takes a connection from the pool, calls fn with it,
+// and returns the connection to the pool.
+// Do does not start a transaction and does not retry fn,
+// since any changes fn made before failing cannot be undone.
+// Use [Pool.Transact] to run fn in a transaction.
+func (p *Pool) Do(ctx context.Context, fn func(*sqlite.Conn) error) error {
+	conn, err := p.Take(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.Put(conn)
+	return fn(conn)
+}
+
+// Transact takes a connection from the pool, starts a transaction,
+// and calls fn with the connection.
+// If fn returns nil, the transaction is committed.
+// Otherwise, the transaction is rolled back and fn's error is returned.
+// If fn panics, the transaction is rolled back
+// and the connection is returned to the pool before the panic continues.
+//
+// If fn or committing the transaction fails with a retryable error
+// (see [TxOptions.MaxRetries]),
+// then Transact rolls back, returns the connection to the pool,
+// waits, and runs the whole transaction again.
+// fn must therefore be safe to call multiple times.
+//
+// The context is used for taking the connection
+// and to interrupt the connection while fn runs.
+// If the context is done while waiting to retry,
+// Transact returns the last error.
+func (p *Pool) Transact(ctx context.Context, opts TxOptions, fn func(*sqlite.Conn) error) error {
+	delay := opts.RetryDelay
+	if delay <= 0 {
+		delay = 10 * time.Millisecond
+	}
+	maxDelay := opts.MaxRetryDelay
+	if maxDelay <= 0 {
+		maxDelay = 1 * time.Second
+	}
+	limit := maxRetries(opts.MaxRetries)
+	for retries := 0; ; retries++ {
+		err := p.transactOnce(ctx, opts.Mode, fn)
+		if err == nil || !isRetryable(err) || retries >= limit {
+			return err
+		}
+		if sleep(ctx, delay) != nil {
+			return err
+		}
+		delay = min(delay*2, maxDelay)
+	}
+}
+
+func (p *Pool) transactOnce(ctx context.Context, mode TxMode, fn func(*sqlite.Conn) error) (err error) {
+	conn, err := p.Take(ctx)
+	if err != nil {
+		return err
+	}
+	defer p.Put(conn)
+	endFn, err := transaction(conn, mode.String())
+	if err != nil {
+		return err
+	}
+	defer endFn(&err)
+	return fn(conn)
+}
+
+// isRetryable reports whether err indicates
+// that a transaction failed because of contention with another connection.
+func isRetryable(err error) bool {
+	code := sqlite.ErrCode(err)
+	switch code.ToPrimary() {
+	case sqlite.ResultBusy, sqlite.ResultLocked:
+		return true
+	}
+	return code == sqlite.ResultErrorSnapshot
+}
@@ -0,0 +1,190 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlitex_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestPoolTransact(t *testing.T) {
+	ctx := context.Background()
+	pool, err := sqlitex.NewPool(filepath.Join(t.TempDir(), "transact.db"), sqlitex.PoolOptions{PoolSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+	err = pool.Do(ctx, func(conn *sqlite.Conn) error {
+		return sqlitex.ExecuteTransient(conn, "CREATE TABLE foo (x INTEGER);", nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := func(t *testing.T) int {
+		t.Helper()
+		var n int
+		err := pool.Do(ctx, func(conn *sqlite.Conn) (err error) {
+			n, err = sqlitex.ResultInt(conn.Prep("SELECT count(*) FROM foo;"))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := pool.Transact(ctx, sqlitex.TxOptions{Mode: sqlitex.TxImmediate}, func(conn *sqlite.Conn) error {
+			if conn.AutocommitEnabled() {
+				t.Error("fn called outside transaction")
+			}
+			return sqlitex.ExecuteTransient(conn, "INSERT INTO foo VALUES (1);", nil)
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := count(t); got != 1 {
+			t.Errorf("count(*) = %d; want 1", got)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		fnErr := errors.New("bork")
+		calls := 0
+		err := pool.Transact(ctx, sqlitex.TxOptions{}, func(conn *sqlite.Conn) error {
+			calls++
+			if err := sqlitex.ExecuteTransient(conn, "INSERT INTO foo VALUES (2);", nil); err != nil {
+				return err
+			}
+			return fnErr
+		})
+		if !errors.Is(err, fnErr) {
+			t.Errorf("Transact(...) = %v; want %v", err, fnErr)
+		}
+		if calls != 1 {
+			t.Errorf("fn called %d times; want 1", calls)
+		}
+		if got := count(t); got != 1 {
+			t.Errorf("count(*) = %d; want 1", got)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		calls := 0
+		err := pool.Transact(ctx, sqlitex.TxOptions{RetryDelay: time.Millisecond}, func(conn *sqlite.Conn) error {
+			calls++
+			if err := sqlitex.ExecuteTransient(conn, "INSERT INTO foo VALUES (3);", nil); err != nil {
+				return err
+			}
+			if calls < 3 {
+				return sqlite.ResultBusySnapshot.ToError()
+			}
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		if calls != 3 {
+			t.Errorf("fn called %d times; want 3", calls)
+		}
+		if got := count(t); got != 2 {
+			t.Errorf("count(*) = %d; want 2", got)
+		}
+	})
+
+	t.Run("NoRetry", func(t *testing.T) {
+		calls := 0
+		err := pool.Transact(ctx, sqlitex.TxOptions{MaxRetries: -1}, func(conn *sqlite.Conn) error {
+			calls++
+			return sqlite.ResultLocked.ToError()
+		})
+		if got, want := sqlite.ErrCode(err), sqlite.ResultLocked; got != want {
+			t.Errorf("Transact(...) error code = %v; want %v", got, want)
+		}
+		if calls != 1 {
+			t.Errorf("fn called %d times; want 1", calls)
+		}
+	})
+
+	t.Run("MaxRetries", func(t *testing.T) {
+		calls := 0
+		err := pool.Transact(ctx, sqlitex.TxOptions{MaxRetries: 2, RetryDelay: time.Millisecond}, func(conn *sqlite.Conn) error {
+			calls++
+			return sqlite.ResultBusy.ToError()
+		})
+		if got, want := sqlite.ErrCode(err), sqlite.ResultBusy; got != want {
+			t.Errorf("Transact(...) error code = %v; want %v", got, want)
+		}
+		if calls != 3 {
+			t.Errorf("fn called %d times; want 3", calls)
+		}
+	})
+}
+
+func TestPoolTransactContended(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "contended.db")
+	pool, err := sqlitex.NewPool(dbPath, sqlitex.PoolOptions{
+		PoolSize: 1,
+		PrepareConn: func(conn *sqlite.Conn) error {
+			// Fail with SQLITE_BUSY instead of waiting for the lock.
+			conn.SetBusyTimeout(0)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := pool.Close(); err != nil {
+			t.Error("Close:", err)
+		}
+	}()
+	err = pool.Do(ctx, func(conn *sqlite.Conn) error {
+		return sqlitex.ExecuteTransient(conn, "CREATE TABLE foo (x INTEGER);", nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blocker, err := sqlite.OpenConn(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := blocker.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(blocker, "BEGIN IMMEDIATE;", nil); err != nil {
+		t.Fatal(err)
+	}
+	defer sqlitex.ExecuteTransient(blocker, "ROLLBACK;", nil)
+
+	calls := 0
+	opts := sqlitex.TxOptions{
+		RetryDelay:    time.Microsecond,
+		MaxRetryDelay: time.Microsecond,
+	}
+	err = pool.Transact(ctx, opts, func(conn *sqlite.Conn) error {
+		calls++
+		return sqlitex.ExecuteTransient(conn, "INSERT INTO foo VALUES (1);", nil)
+	})
+	if got, want := sqlite.ErrCode(err).ToPrimary(), sqlite.ResultBusy; got != want {
+		t.Errorf("Transact(...) = %v; want code %v", err, want)
+	}
+	if want := sqlitex.DefaultMaxRetries + 1; calls != want {
+		t.Errorf("fn called %d times; want %d", calls, want)
+	}
+}
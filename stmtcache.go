@@ -0,0 +1,106 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	lib "modernc.org/sqlite/lib"
+)
+
+// StmtCacheStats is a snapshot of the statistics
+// for a connection's cache of persistent prepared statements.
+type StmtCacheStats struct {
+	// Size is the number of statements currently in the cache.
+	Size int
+	// Hits is the number of calls to [Conn.Prepare]
+	// that returned a cached statement.
+	Hits int64
+	// Misses is the number of calls to [Conn.Prepare]
+	// that prepared a new statement.
+	Misses int64
+	// Evictions is the number of statements finalized
+	// to keep the cache within its size limit.
+	Evictions int64
+}
+
+// SetStmtCacheSize limits the number of persistent prepared statements
+// cached by [Conn.Prep] and [Conn.Prepare] to n.
+// When the limit is exceeded,
+// the least recently used statements are finalized.
+// A statement returned by Prep or Prepare is in use
+// until it is reset with [Stmt.Reset]
+// or [Stmt.Step] reports that it has no more rows,
+// and statements in use are never finalized by the cache:
+// the cache grows past its limit until they are done.
+// If n is zero or negative, the cache is unbounded, which is the default.
+//
+// A statement that is no longer in use must not be used
+// after n more distinct queries are prepared on the connection,
+// since it may have been finalized.
+// Stepping a finalized statement returns an error.
+func (c *Conn) SetStmtCacheSize(n int) {
+	if c == nil {
+		return
+	}
+	c.stmtCacheSize = max(n, 0)
+	c.evictStmts()
+}
+
+// StmtCacheStats returns the statistics for the connection's statement cache.
+func (c *Conn) StmtCacheStats() StmtCacheStats {
+	if c == nil {
+		return StmtCacheStats{}
+	}
+	stats := c.stmtCacheStats
+	stats.Size = len(c.stmts)
+	return stats
+}
+
+// ClearStmtCache finalizes all cached persistent prepared statements
+// that are not in use.
+// Statements returned by [Conn.Prep] or [Conn.Prepare]
+// must not be used after they are reset or stepped to completion
+// and ClearStmtCache is called.
+func (c *Conn) ClearStmtCache() {
+	if c == nil {
+		return
+	}
+	for e := c.stmtLRU.Back(); e != nil; {
+		prev := e.Prev()
+		if stmt := e.Value.(*Stmt); !stmt.inUse() {
+			stmt.Finalize()
+		}
+		e = prev
+	}
+}
+
+// evictStmts finalizes the least recently used statements
+// until the cache is within its size limit.
+// The most recently used statement is never evicted.
+func (c *Conn) evictStmts() {
+	if c.stmtCacheSize <= 0 {
+		return
+	}
+	front := c.stmtLRU.Front()
+	for e := c.stmtLRU.Back(); e != nil && e != front && len(c.stmts) > c.stmtCacheSize; {
+		prev := e.Prev()
+		if stmt := e.Value.(*Stmt); !stmt.inUse() {
+			stmt.Finalize()
+			c.stmtCacheStats.Evictions++
+		}
+		e = prev
+	}
+}
+
+// inUse reports whether the statement has been returned by [Conn.Prepare]
+// or stepped, and has not since been reset or stepped to completion.
+func (stmt *Stmt) inUse() bool {
+	return stmt.pinned || stmt.busy()
+}
+
+// busy reports whether the statement has been stepped but not reset.
+//
+// https://www.sqlite.org/c3ref/stmt_busy.html
+func (stmt *Stmt) busy() bool {
+	return stmt.lastHasRow || lib.Xsqlite3_stmt_busy(stmt.conn.tls, stmt.stmt) != 0
+}
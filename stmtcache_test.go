@@ -0,0 +1,140 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+func TestStmtCache(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	c.SetStmtCacheSize(2)
+
+	query := func(i int) string {
+		return fmt.Sprintf("SELECT %d;", i)
+	}
+	prep := func(t *testing.T, query string) {
+		t.Helper()
+		stmt := c.Prep(query)
+		if _, err := stmt.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		prep(t, query(i))
+	}
+	prep(t, query(3)) // hit
+	want := sqlite.StmtCacheStats{Size: 2, Hits: 1, Misses: 4, Evictions: 2}
+	if got := c.StmtCacheStats(); got != want {
+		t.Errorf("StmtCacheStats() = %+v; want %+v", got, want)
+	}
+
+	t.Run("Stepping", func(t *testing.T) {
+		stmt := c.Prep("SELECT 1 UNION ALL SELECT 2;")
+		if hasRow, err := stmt.Step(); err != nil || !hasRow {
+			t.Fatalf("stmt.Step() = %t, %v; want true, <nil>", hasRow, err)
+		}
+		// Preparing other statements must not finalize the stepping statement.
+		for i := 10; i < 14; i++ {
+			prep(t, query(i))
+		}
+		if hasRow, err := stmt.Step(); err != nil || !hasRow {
+			t.Fatalf("stmt.Step() = %t, %v; want true, <nil>", hasRow, err)
+		}
+		if got := stmt.ColumnInt(0); got != 2 {
+			t.Errorf("second row = %d; want 2", got)
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+		prep(t, query(14))
+		if got := c.StmtCacheStats().Size; got != 2 {
+			t.Errorf("StmtCacheStats().Size = %d; want 2", got)
+		}
+	})
+
+	t.Run("Held", func(t *testing.T) {
+		stmt := c.Prep("SELECT 42;")
+		// Preparing more statements than the cache holds
+		// must not finalize a statement that has not been reset.
+		for i := 20; i < 25; i++ {
+			prep(t, query(i))
+		}
+		if hasRow, err := stmt.Step(); err != nil || !hasRow {
+			t.Fatalf("stmt.Step() = %t, %v; want true, <nil>", hasRow, err)
+		}
+		if got := stmt.ColumnInt(0); got != 42 {
+			t.Errorf("stmt.ColumnInt(0) = %d; want 42", got)
+		}
+		if err := stmt.Reset(); err != nil {
+			t.Fatal(err)
+		}
+
+		// Once reset, the statement can be evicted,
+		// and using it afterward reports an error.
+		for i := 30; i < 33; i++ {
+			prep(t, query(i))
+		}
+		if _, err := stmt.Step(); err == nil {
+			t.Error("stmt.Step() on evicted statement did not return an error")
+		}
+		if err := stmt.Reset(); err == nil {
+			t.Error("stmt.Reset() on evicted statement did not return an error")
+		}
+		// Binding and reading columns must not panic.
+		stmt.BindInt64(1, 1)
+		stmt.BindTime(1, time.Now())
+		if got := stmt.ColumnInt(0); got != 0 {
+			t.Errorf("stmt.ColumnInt(0) on evicted statement = %d; want 0", got)
+		}
+		if got := stmt.ColumnText(0); got != "" {
+			t.Errorf("stmt.ColumnText(0) on evicted statement = %q; want \"\"", got)
+		}
+		if got := stmt.ColumnType(0); got != sqlite.TypeNull {
+			t.Errorf("stmt.ColumnType(0) on evicted statement = %v; want %v", got, sqlite.TypeNull)
+		}
+		if got, err := stmt.ColumnTime(0); err != nil || !got.IsZero() {
+			t.Errorf("stmt.ColumnTime(0) on evicted statement = %v, %v; want zero time, <nil>", got, err)
+		}
+		if err := stmt.Finalize(); err != nil {
+			t.Errorf("stmt.Finalize() on evicted statement: %v", err)
+		}
+	})
+
+	t.Run("SteppedToCompletion", func(t *testing.T) {
+		// Statements that are stepped until they have no more rows
+		// are done even if they are never reset.
+		for i := 40; i < 45; i++ {
+			stmt := c.Prep(fmt.Sprintf("SELECT %d WHERE false;", i))
+			if hasRow, err := stmt.Step(); err != nil || hasRow {
+				t.Fatalf("stmt.Step() = %t, %v; want false, <nil>", hasRow, err)
+			}
+		}
+		if got := c.StmtCacheStats().Size; got != 2 {
+			t.Errorf("StmtCacheStats().Size = %d; want 2", got)
+		}
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		c.ClearStmtCache()
+		if got := c.StmtCacheStats().Size; got != 0 {
+			t.Errorf("after ClearStmtCache, StmtCacheStats().Size = %d; want 0", got)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// TimeFormat is a storage format for [time.Time] values.
+// SQLite has no dedicated time type:
+// its date and time functions understand text, integers, and floats.
+// See https://www.sqlite.org/lang_datefunc.html for details.
+type TimeFormat int
+
+// Time formats.
+const (
+	// TimeFormatRFC3339 stores times as TEXT in RFC 3339 format
+	// in UTC with nanosecond precision,
+	// like "2006-01-02T15:04:05.000000000Z".
+	// The fixed width keeps the text sortable.
+	// This is the default.
+	TimeFormatRFC3339 TimeFormat = iota
+	// TimeFormatUnix stores times as INTEGER seconds since the Unix epoch.
+	// Fractions of a second are truncated.
+	TimeFormatUnix
+	// TimeFormatUnixMilli stores times as INTEGER milliseconds since the Unix epoch.
+	// Fractions of a millisecond are truncated.
+	TimeFormatUnixMilli
+	// TimeFormatJulianDay stores times as a REAL Julian day number,
+	// the format returned by SQLite's julianday() function.
+	TimeFormatJulianDay
+)
+
+// String returns the name of the format's constant.
+func (format TimeFormat) String() string {
+	switch format {
+	case TimeFormatRFC3339:
+		return "TimeFormatRFC3339"
+	case TimeFormatUnix:
+		return "TimeFormatUnix"
+	case TimeFormatUnixMilli:
+		return "TimeFormatUnixMilli"
+	case TimeFormatJulianDay:
+		return "TimeFormatJulianDay"
+	default:
+		return fmt.Sprintf("TimeFormat(%d)", int(format))
+	}
+}
+
+const rfc3339Fixed = "2006-01-02T15:04:05.000000000Z07:00"
+
+// timeLayouts are the text layouts accepted by [Stmt.ColumnTime],
+// which include the time value formats that SQLite's date and time functions accept.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04Z07:00",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// unixEpochJulianDay is the Julian day number of the Unix epoch.
+const unixEpochJulianDay = 2440587.5
+
+// SetTimeFormat sets the format that [Stmt.BindTime]
+// and [Stmt.SetTime] use to store times on statements of the connection.
+// It also determines how [Stmt.ColumnTime] interprets numbers.
+// The default is [TimeFormatRFC3339].
+func (c *Conn) SetTimeFormat(format TimeFormat) {
+	c.timeFormat = format
+}
+
+// TimeFormat returns the format set by [Conn.SetTimeFormat].
+func (c *Conn) TimeFormat() TimeFormat {
+	return c.timeFormat
+}
+
+// BindTime binds value to a numbered stmt parameter
+// in the connection's [TimeFormat].
+//
+// Parameter indices start at 1.
+func (stmt *Stmt) BindTime(param int, value time.Time) {
+	if stmt.stmt == 0 {
+		return
+	}
+	switch stmt.conn.timeFormat {
+	case TimeFormatUnix:
+		stmt.BindInt64(param, value.Unix())
+	case TimeFormatUnixMilli:
+		stmt.BindInt64(param, value.UnixMilli())
+	case TimeFormatJulianDay:
+		stmt.BindFloat(param, float64(value.UnixMilli())/(24*60*60*1000)+unixEpochJulianDay)
+	default:
+		stmt.BindText(param, value.UTC().Format(rfc3339Fixed))
+	}
+}
+
+// SetTime binds a time to a parameter using a column name
+// in the connection's [TimeFormat].
+// An invalid parameter name will cause the call to Step to return an error.
+func (stmt *Stmt) SetTime(param string, value time.Time) {
+	stmt.BindTime(stmt.findBindName("SetTime", param), value)
+}
+
+// ColumnTime returns a query result value as a time.
+// NULL is returned as the zero time.
+//
+// Text is parsed in RFC 3339 format
+// or any of the other formats that SQLite's date and time functions accept,
+// such as "2006-01-02 15:04:05".
+// Text without a time zone is in UTC.
+// Integers are seconds since the Unix epoch,
+// or milliseconds if the connection's [TimeFormat] is [TimeFormatUnixMilli].
+// Floats are Julian day numbers,
+// unless the connection's TimeFormat is [TimeFormatUnix] or TimeFormatUnixMilli,
+// in which case they are fractional seconds or milliseconds since the Unix epoch.
+// Numbers are returned in UTC.
+//
+// Column indices start at 0.
+func (stmt *Stmt) ColumnTime(col int) (time.Time, error) {
+	typ := stmt.ColumnType(col)
+	if typ == TypeNull {
+		return time.Time{}, nil
+	}
+	format := stmt.conn.timeFormat
+	switch typ {
+	case TypeInteger:
+		i := stmt.ColumnInt64(col)
+		if format == TimeFormatUnixMilli {
+			return time.UnixMilli(i).UTC(), nil
+		}
+		return time.Unix(i, 0).UTC(), nil
+	case TypeFloat:
+		f := stmt.ColumnFloat(col)
+		var ms float64
+		switch format {
+		case TimeFormatUnix:
+			ms = f * 1000
+		case TimeFormatUnixMilli:
+			ms = f
+		default:
+			ms = (f - unixEpochJulianDay) * (24 * 60 * 60 * 1000)
+		}
+		return time.UnixMilli(int64(math.Round(ms))).UTC(), nil
+	case TypeText:
+		s := stmt.ColumnText(col)
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("sqlite: column %s: parse time %q: unknown format", stmt.ColumnName(col), s)
+	default:
+		return time.Time{}, fmt.Errorf("sqlite: column %s: cannot convert %v to time", stmt.ColumnName(col), typ)
+	}
+}
+
+// GetTime returns a query result value for colName as a time.
+// See [Stmt.ColumnTime] for the accepted formats.
+func (stmt *Stmt) GetTime(colName string) (time.Time, error) {
+	col, found := stmt.colNames[colName]
+	if !found {
+		return time.Time{}, nil
+	}
+	return stmt.ColumnTime(col)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"testing"
+	"time"
+
+	"zombiezen.com/go/sqlite"
+)
+
+func TestTime(t *testing.T) {
+	conn, err := sqlite.OpenConn(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	value := time.Date(2024, time.March, 4, 5, 6, 7, 890_000_000, time.FixedZone("X", -2*60*60))
+	tests := []struct {
+		format   sqlite.TimeFormat
+		wantType sqlite.ColumnType
+		want     time.Time
+	}{
+		{sqlite.TimeFormatRFC3339, sqlite.TypeText, value},
+		{sqlite.TimeFormatUnix, sqlite.TypeInteger, value.Truncate(time.Second)},
+		{sqlite.TimeFormatUnixMilli, sqlite.TypeInteger, value},
+		{sqlite.TimeFormatJulianDay, sqlite.TypeFloat, value},
+	}
+	for _, test := range tests {
+		t.Run(test.format.String(), func(t *testing.T) {
+			conn.SetTimeFormat(test.format)
+			stmt, _, err := conn.PrepareTransient(`SELECT :t, datetime(:t, 'auto');`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer stmt.Finalize()
+			stmt.SetTime(":t", value)
+			if _, err := stmt.Step(); err != nil {
+				t.Fatal(err)
+			}
+			if got := stmt.ColumnType(0); got != test.wantType {
+				t.Errorf("type = %v; want %v", got, test.wantType)
+			}
+			got, err := stmt.ColumnTime(0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(test.want) {
+				t.Errorf("ColumnTime(0) = %v; want %v", got, test.want)
+			}
+			if got, want := stmt.ColumnText(1), "2024-03-04 07:06:07"; test.format != sqlite.TimeFormatUnixMilli && got != want {
+				t.Errorf("datetime(:t, 'auto') = %q; want %q", got, want)
+			}
+		})
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		conn.SetTimeFormat(sqlite.TimeFormatRFC3339)
+		stmt, _, err := conn.PrepareTransient(`SELECT '2024-03-04 07:06:07', '2024-03-04', julianday('2024-03-04 07:06:07'), 1709535967, NULL;`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		if _, err := stmt.Step(); err != nil {
+			t.Fatal(err)
+		}
+		wants := []time.Time{
+			time.Date(2024, time.March, 4, 7, 6, 7, 0, time.UTC),
+			time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 4, 7, 6, 7, 0, time.UTC),
+			time.Date(2024, time.March, 4, 7, 6, 7, 0, time.UTC),
+			{},
+		}
+		for col, want := range wants {
+			got, err := stmt.ColumnTime(col)
+			if err != nil {
+				t.Errorf("ColumnTime(%d): %v", col, err)
+				continue
+			}
+			if !got.Equal(want) {
+				t.Errorf("ColumnTime(%d) = %v; want %v", col, got, want)
+			}
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		stmt, _, err := conn.PrepareTransient(`SELECT 'yesterday';`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Finalize()
+		if _, err := stmt.Step(); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := stmt.ColumnTime(0); err == nil {
+			t.Errorf("ColumnTime(0) = %v, <nil>; want error", got)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unsafe"
+
+	"modernc.org/libc"
+	lib "modernc.org/sqlite/lib"
+)
+
+// TraceMask is a bitmask of [trace event codes]
+// used to select which events are passed to [Conn.SetTrace].
+//
+// [trace event codes]: https://sqlite.org/c3ref/c_trace.html
+type TraceMask uint32
+
+// Trace event codes.
+const (
+	// TraceStmt is sent when a prepared statement first begins running
+	// and possibly at other times during the execution of the statement
+	// (e.g. at the start of each trigger subprogram).
+	TraceStmt TraceMask = lib.SQLITE_TRACE_STMT
+	// TraceProfile is sent when a statement finishes
+	// and includes the approximate wall-clock time the statement took to run.
+	TraceProfile TraceMask = lib.SQLITE_TRACE_PROFILE
+	// TraceRow is sent whenever a prepared statement generates a single row of result.
+	TraceRow TraceMask = lib.SQLITE_TRACE_ROW
+	// TraceClose is sent when the database connection closes.
+	TraceClose TraceMask = lib.SQLITE_TRACE_CLOSE
+)
+
+// String returns a pipe-separated list of the C constant names set in mask.
+func (mask TraceMask) String() string {
+	var parts []string
+	if mask&TraceStmt != 0 {
+		parts = append(parts, "SQLITE_TRACE_STMT")
+		mask &^= TraceStmt
+	}
+	if mask&TraceProfile != 0 {
+		parts = append(parts, "SQLITE_TRACE_PROFILE")
+		mask &^= TraceProfile
+	}
+	if mask&TraceRow != 0 {
+		parts = append(parts, "SQLITE_TRACE_ROW")
+		mask &^= TraceRow
+	}
+	if mask&TraceClose != 0 {
+		parts = append(parts, "SQLITE_TRACE_CLOSE")
+		mask &^= TraceClose
+	}
+	if mask != 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%#x", uint32(mask)))
+	}
+	return strings.Join(parts, "|")
+}
+
+// TraceEvent is a single event passed to the function registered with [Conn.SetTrace].
+type TraceEvent struct {
+	// Type is exactly one of TraceStmt, TraceProfile, TraceRow, or TraceClose.
+	Type TraceMask
+	// Stmt is the statement that generated the event.
+	// It is nil for TraceClose events.
+	// The trace function must not call methods on Stmt
+	// that step, reset, or finalize the statement.
+	Stmt *Stmt
+	// SQL is the text of the statement with bound parameters expanded
+	// for TraceStmt events.
+	// When a trigger subprogram starts,
+	// SQL is instead a comment that identifies the trigger.
+	SQL string
+	// Duration is the approximate wall-clock time the statement took to run
+	// for TraceProfile events.
+	Duration time.Duration
+}
+
+// SetTrace registers a function that is called for the events selected by mask.
+// The trace function must not modify the database connection,
+// including by preparing or running statements.
+//
+// SetTrace(0, nil) clears any trace function previously set.
+//
+// https://sqlite.org/c3ref/trace_v2.html
+func (c *Conn) SetTrace(mask TraceMask, fn func(TraceEvent)) error {
+	if c == nil {
+		return fmt.Errorf("sqlite: set trace: nil connection")
+	}
+	if mask == 0 || fn == nil {
+		res := ResultCode(lib.Xsqlite3_trace_v2(c.tls, c.conn, 0, 0, 0))
+		hooks.mu.Lock()
+		delete(hooks.trace, c.conn)
+		hooks.mu.Unlock()
+		if err := res.ToError(); err != nil {
+			return fmt.Errorf("sqlite: set trace: %w", err)
+		}
+		return nil
+	}
+
+	hooks.mu.Lock()
+	if hooks.trace == nil {
+		hooks.trace = make(map[uintptr]func(TraceEvent))
+	}
+	hooks.trace[c.conn] = fn
+	hooks.mu.Unlock()
+
+	xTrace := cFuncPointer(traceTrampoline)
+	res := ResultCode(lib.Xsqlite3_trace_v2(c.tls, c.conn, uint32(mask), xTrace, c.conn))
+	if err := res.ToError(); err != nil {
+		return fmt.Errorf("sqlite: set trace: %w", err)
+	}
+	return nil
+}
+
+func traceTrampoline(tls *libc.TLS, eventType uint32, conn uintptr, p uintptr, x uintptr) int32 {
+	hooks.mu.RLock()
+	fn := hooks.trace[conn]
+	hooks.mu.RUnlock()
+	if fn == nil {
+		return 0
+	}
+	event := TraceEvent{Type: TraceMask(eventType)}
+	if event.Type != TraceClose {
+		allConns.mu.RLock()
+		c := allConns.table[conn]
+		allConns.mu.RUnlock()
+		if c != nil {
+			event.Stmt = c.liveStmts[p]
+		}
+	}
+	switch event.Type {
+	case TraceStmt:
+		event.SQL = libc.GoString(x)
+		if !strings.HasPrefix(event.SQL, "--") {
+			if expanded := lib.Xsqlite3_expanded_sql(tls, p); expanded != 0 {
+				event.SQL = libc.GoString(expanded)
+				lib.Xsqlite3_free(tls, expanded)
+			}
+		}
+	case TraceProfile:
+		event.Duration = time.Duration(*(*int64)(unsafe.Pointer(x)))
+	}
+	fn(event)
+	return 0
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"zombiezen.com/go/sqlite"
+)
+
+func TestSetTrace(t *testing.T) {
+	c, err := sqlite.OpenConn(":memory:", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type event struct {
+		Type     sqlite.TraceMask
+		SameStmt bool
+		SQL      string
+	}
+	var stmt *sqlite.Stmt
+	var got []event
+	err = c.SetTrace(sqlite.TraceStmt|sqlite.TraceProfile|sqlite.TraceRow|sqlite.TraceClose, func(e sqlite.TraceEvent) {
+		ev := event{Type: e.Type, SameStmt: e.Stmt == stmt, SQL: e.SQL}
+		if e.Type == sqlite.TraceProfile && e.Duration < 0 {
+			t.Errorf("profile duration = %v; want >= 0", e.Duration)
+		}
+		got = append(got, ev)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stmt, _, err = c.PrepareTransient("SELECT ?1 UNION ALL SELECT 2;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stmt.BindInt64(1, 1)
+	for {
+		hasRow, err := stmt.Step()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !hasRow {
+			break
+		}
+	}
+	if err := stmt.Finalize(); err != nil {
+		t.Error(err)
+	}
+	stmt = nil
+	if err := c.Close(); err != nil {
+		t.Error(err)
+	}
+
+	want := []event{
+		{Type: sqlite.TraceStmt, SameStmt: true, SQL: "SELECT 1 UNION ALL SELECT 2;"},
+		{Type: sqlite.TraceRow, SameStmt: true},
+		{Type: sqlite.TraceRow, SameStmt: true},
+		{Type: sqlite.TraceProfile, SameStmt: true},
+		{Type: sqlite.TraceClose, SameStmt: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("events (-want +got):\n%s", diff)
+	}
+}
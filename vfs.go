@@ -0,0 +1,668 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"modernc.org/libc"
+	"modernc.org/libc/sys/types"
+	lib "modernc.org/sqlite/lib"
+)
+
+// A VFS is an [operating system interface] that SQLite uses
+// to access files.
+// VFSes are registered with [RegisterVFS]
+// and selected for a connection with the "vfs" [URI parameter]
+// (e.g. "file:foo.db?vfs=myvfs" opened with [OpenURI]).
+//
+// Methods on a VFS may be called concurrently from multiple connections.
+// Errors returned from VFS methods are reported to SQLite
+// with the error's [ErrCode] if it has one,
+// or an appropriate I/O error code otherwise.
+//
+// [operating system interface]: https://sqlite.org/vfs.html
+// [URI parameter]: https://sqlite.org/uri.html#urivfs
+type VFS interface {
+	// Open opens the named file.
+	// flags contains the flags passed to open the connection
+	// combined with exactly one file type flag (e.g. [OpenMainDB] or [OpenMainJournal]).
+	// If name is empty, Open must create a temporary file
+	// that is deleted when it is closed.
+	// Open returns the flags the file was actually opened with,
+	// which may include [OpenReadOnly]
+	// if a read-write open was requested but was not possible.
+	Open(name string, flags OpenFlags) (VFSFile, OpenFlags, error)
+	// Delete removes the named file.
+	// If syncDir is true, then Delete should not return
+	// until the directory change has been made durable.
+	// If the file does not exist, Delete should return an error
+	// for which errors.Is(err, fs.ErrNotExist) reports true.
+	Delete(name string, syncDir bool) error
+	// Access reports whether the named file exists
+	// or whether it is readable or writable, depending on flag.
+	Access(name string, flag AccessFlag) (bool, error)
+	// FullPathname returns the canonical form of the given path.
+	// SQLite uses the result when opening the file
+	// and to detect whether two connections refer to the same database.
+	FullPathname(name string) (string, error)
+}
+
+// A VFSFile is a file opened by a [VFS].
+// SQLite serializes calls to the methods of any single VFSFile.
+//
+// The byte slices passed to ReadAt and WriteAt
+// refer to memory owned by SQLite:
+// implementations must not retain them after returning.
+type VFSFile interface {
+	// ReadAt reads len(p) bytes into p starting at offset off
+	// following the [io.ReaderAt] contract.
+	// Reads past the end of the file
+	// must return the number of bytes read and [io.EOF].
+	ReadAt(p []byte, off int64) (n int, err error)
+	// WriteAt writes len(p) bytes from p starting at offset off
+	// following the [io.WriterAt] contract.
+	WriteAt(p []byte, off int64) (n int, err error)
+	// Truncate changes the size of the file.
+	Truncate(size int64) error
+	// Sync commits the file's contents to stable storage.
+	Sync(flags SyncFlag) error
+	// FileSize returns the size of the file in bytes.
+	FileSize() (int64, error)
+	// Lock upgrades the file's lock to the given level.
+	// If another file holds a conflicting lock,
+	// Lock should return a [ResultBusy] error.
+	Lock(level LockLevel) error
+	// Unlock downgrades the file's lock to the given level,
+	// which is always [LockShared] or [LockNone].
+	Unlock(level LockLevel) error
+	// CheckReservedLock reports whether any file
+	// holds a [LockReserved] or greater lock on the file.
+	CheckReservedLock() (bool, error)
+	// SectorSize returns the size in bytes of the smallest unit
+	// that can be written to the file without disturbing adjacent bytes.
+	SectorSize() int
+	// DeviceCharacteristics returns the I/O capabilities of the file.
+	DeviceCharacteristics() DeviceCharacteristics
+	// Close releases any resources associated with the file.
+	Close() error
+}
+
+// AccessFlag is the check performed by [VFS] Access.
+type AccessFlag int32
+
+// Access flags.
+const (
+	// AccessExists checks whether the file exists.
+	AccessExists AccessFlag = lib.SQLITE_ACCESS_EXISTS
+	// AccessReadWrite checks whether the file is both readable and writable.
+	AccessReadWrite AccessFlag = lib.SQLITE_ACCESS_READWRITE
+	// AccessRead checks whether the file is readable.
+	AccessRead AccessFlag = lib.SQLITE_ACCESS_READ
+)
+
+// String returns the C constant name of the flag.
+func (flag AccessFlag) String() string {
+	switch flag {
+	case AccessExists:
+		return "SQLITE_ACCESS_EXISTS"
+	case AccessReadWrite:
+		return "SQLITE_ACCESS_READWRITE"
+	case AccessRead:
+		return "SQLITE_ACCESS_READ"
+	default:
+		return fmt.Sprintf("AccessFlag(%d)", int32(flag))
+	}
+}
+
+// SyncFlag is a bitmask of options passed to [VFSFile] Sync.
+type SyncFlag int32
+
+// Sync flags. Exactly one of SyncNormal or SyncFull is set.
+const (
+	// SyncNormal requests normal fsync semantics.
+	SyncNormal SyncFlag = lib.SQLITE_SYNC_NORMAL
+	// SyncFull requests Mac OS X style fullsync semantics.
+	SyncFull SyncFlag = lib.SQLITE_SYNC_FULL
+	// SyncDataOnly indicates that only the file's data
+	// and not its metadata (e.g. modification time) needs to be synced.
+	SyncDataOnly SyncFlag = lib.SQLITE_SYNC_DATAONLY
+)
+
+// String returns a pipe-separated list of the C constant names set in flags.
+func (flags SyncFlag) String() string {
+	var parts []string
+	switch flags &^ SyncDataOnly {
+	case SyncNormal:
+		parts = append(parts, "SQLITE_SYNC_NORMAL")
+		flags &^= SyncNormal
+	case SyncFull:
+		parts = append(parts, "SQLITE_SYNC_FULL")
+		flags &^= SyncFull
+	}
+	if flags&SyncDataOnly != 0 {
+		parts = append(parts, "SQLITE_SYNC_DATAONLY")
+		flags &^= SyncDataOnly
+	}
+	if flags != 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%#x", int32(flags)))
+	}
+	return strings.Join(parts, "|")
+}
+
+// LockLevel is a [file locking level] used by [VFSFile].
+//
+// [file locking level]: https://sqlite.org/lockingv3.html
+type LockLevel int32
+
+// Lock levels, in increasing order of exclusivity.
+const (
+	LockNone      LockLevel = lib.SQLITE_LOCK_NONE
+	LockShared    LockLevel = lib.SQLITE_LOCK_SHARED
+	LockReserved  LockLevel = lib.SQLITE_LOCK_RESERVED
+	LockPending   LockLevel = lib.SQLITE_LOCK_PENDING
+	LockExclusive LockLevel = lib.SQLITE_LOCK_EXCLUSIVE
+)
+
+// String returns the C constant name of the lock level.
+func (level LockLevel) String() string {
+	switch level {
+	case LockNone:
+		return "SQLITE_LOCK_NONE"
+	case LockShared:
+		return "SQLITE_LOCK_SHARED"
+	case LockReserved:
+		return "SQLITE_LOCK_RESERVED"
+	case LockPending:
+		return "SQLITE_LOCK_PENDING"
+	case LockExclusive:
+		return "SQLITE_LOCK_EXCLUSIVE"
+	default:
+		return fmt.Sprintf("LockLevel(%d)", int32(level))
+	}
+}
+
+// DeviceCharacteristics is a bitmask of [I/O capabilities]
+// returned by [VFSFile] DeviceCharacteristics.
+//
+// [I/O capabilities]: https://sqlite.org/c3ref/c_iocap_atomic.html
+type DeviceCharacteristics int32
+
+// Device characteristics.
+const (
+	// IOCapAtomic indicates that all writes are atomic.
+	IOCapAtomic DeviceCharacteristics = lib.SQLITE_IOCAP_ATOMIC
+	// IOCapSafeAppend indicates that when data is appended to a file,
+	// the data is appended first then the size of the file is extended,
+	// never the other way around.
+	IOCapSafeAppend DeviceCharacteristics = lib.SQLITE_IOCAP_SAFE_APPEND
+	// IOCapSequential indicates that all writes occur in the order they are issued.
+	IOCapSequential DeviceCharacteristics = lib.SQLITE_IOCAP_SEQUENTIAL
+	// IOCapUndeletableWhenOpen indicates that files cannot be deleted while open.
+	IOCapUndeletableWhenOpen DeviceCharacteristics = lib.SQLITE_IOCAP_UNDELETABLE_WHEN_OPEN
+	// IOCapPowersafeOverwrite indicates that a power failure during a write
+	// will not change bytes outside the range being written.
+	IOCapPowersafeOverwrite DeviceCharacteristics = lib.SQLITE_IOCAP_POWERSAFE_OVERWRITE
+	// IOCapImmutable indicates that the file never changes,
+	// so SQLite can skip locking and change detection.
+	IOCapImmutable DeviceCharacteristics = lib.SQLITE_IOCAP_IMMUTABLE
+)
+
+// RegisterVFS registers vfs under the given name
+// so that connections can select it with the "vfs" URI parameter.
+// If makeDefault is true, then the VFS is also used for connections
+// that do not specify a VFS.
+// Registering a VFS with the name of a VFS previously registered with RegisterVFS
+// replaces the previous VFS for files opened after RegisterVFS returns.
+//
+// VFSes registered with RegisterVFS do not support shared memory,
+// so databases they open can only use [WAL mode]
+// with an exclusive [locking mode].
+//
+// [WAL mode]: https://sqlite.org/wal.html
+// [locking mode]: https://sqlite.org/pragma.html#pragma_locking_mode
+func RegisterVFS(name string, vfs VFS, makeDefault bool) error {
+	if name == "" {
+		return fmt.Errorf("sqlite: register vfs: empty name")
+	}
+	if vfs == nil {
+		return fmt.Errorf("sqlite: register vfs %q: nil vfs", name)
+	}
+	tls := libc.NewTLS()
+	defer tls.Close()
+	initlib(tls)
+
+	xvfses.mu.Lock()
+	defer xvfses.mu.Unlock()
+	pVfs := xvfses.names[name]
+	if pVfs == 0 {
+		var err error
+		pVfs, err = newCVFS(tls, name)
+		if err != nil {
+			return fmt.Errorf("sqlite: register vfs %q: %w", name, err)
+		}
+		xvfses.names[name] = pVfs
+	}
+	xvfses.m[pVfs] = vfs
+
+	res := ResultCode(lib.Xsqlite3_vfs_register(tls, pVfs, boolToInt32(makeDefault)))
+	if err := res.ToError(); err != nil {
+		return fmt.Errorf("sqlite: register vfs %q: %w", name, err)
+	}
+	return nil
+}
+
+// UnregisterVFS unregisters a VFS previously registered with [RegisterVFS].
+// Connections that are using the VFS must be closed first.
+func UnregisterVFS(name string) error {
+	tls := libc.NewTLS()
+	defer tls.Close()
+	initlib(tls)
+
+	xvfses.mu.Lock()
+	defer xvfses.mu.Unlock()
+	pVfs := xvfses.names[name]
+	if pVfs == 0 {
+		return fmt.Errorf("sqlite: unregister vfs %q: not registered", name)
+	}
+	res := ResultCode(lib.Xsqlite3_vfs_unregister(tls, pVfs))
+	if err := res.ToError(); err != nil {
+		return fmt.Errorf("sqlite: unregister vfs %q: %w", name, err)
+	}
+	// The sqlite3_vfs object is retained so that
+	// re-registering the name reuses the same memory.
+	delete(xvfses.m, pVfs)
+	return nil
+}
+
+const vfsMaxPathname = 1024
+
+// newCVFS allocates a sqlite3_vfs object for the given name.
+// It must be called while holding xvfses.mu.
+func newCVFS(tls *libc.TLS, name string) (uintptr, error) {
+	if xvfses.ioMethods == 0 {
+		size := types.Size_t(unsafe.Sizeof(cIOMethods{}))
+		p, err := malloc(tls, size)
+		if err != nil {
+			return 0, err
+		}
+		libc.Xmemset(tls, p, 0, size)
+		methods := (*cIOMethods)(unsafe.Pointer(p))
+		methods.iVersion = 1
+		methods.xClose = cFuncPointer(vfsFileCloseTrampoline)
+		methods.xRead = cFuncPointer(vfsFileReadTrampoline)
+		methods.xWrite = cFuncPointer(vfsFileWriteTrampoline)
+		methods.xTruncate = cFuncPointer(vfsFileTruncateTrampoline)
+		methods.xSync = cFuncPointer(vfsFileSyncTrampoline)
+		methods.xFileSize = cFuncPointer(vfsFileSizeTrampoline)
+		methods.xLock = cFuncPointer(vfsFileLockTrampoline)
+		methods.xUnlock = cFuncPointer(vfsFileUnlockTrampoline)
+		methods.xCheckReservedLock = cFuncPointer(vfsFileCheckReservedLockTrampoline)
+		methods.xFileControl = cFuncPointer(vfsFileControlTrampoline)
+		methods.xSectorSize = cFuncPointer(vfsFileSectorSizeTrampoline)
+		methods.xDeviceCharacteristics = cFuncPointer(vfsFileDeviceCharacteristicsTrampoline)
+		xvfses.ioMethods = p
+	}
+
+	cname, err := libc.CString(name)
+	if err != nil {
+		return 0, err
+	}
+	size := types.Size_t(unsafe.Sizeof(cVFS{}))
+	pVfs, err := malloc(tls, size)
+	if err != nil {
+		libc.Xfree(tls, cname)
+		return 0, err
+	}
+	libc.Xmemset(tls, pVfs, 0, size)
+	cvfs := (*cVFS)(unsafe.Pointer(pVfs))
+	cvfs.iVersion = 2
+	cvfs.szOsFile = int32(unsafe.Sizeof(vfsFileWrapper{}))
+	cvfs.mxPathname = vfsMaxPathname
+	cvfs.zName = cname
+	cvfs.xOpen = cFuncPointer(vfsOpenTrampoline)
+	cvfs.xDelete = cFuncPointer(vfsDeleteTrampoline)
+	cvfs.xAccess = cFuncPointer(vfsAccessTrampoline)
+	cvfs.xFullPathname = cFuncPointer(vfsFullPathnameTrampoline)
+	cvfs.xDlOpen = cFuncPointer(vfsDlOpenTrampoline)
+	cvfs.xDlError = cFuncPointer(vfsDlErrorTrampoline)
+	cvfs.xDlSym = cFuncPointer(vfsDlSymTrampoline)
+	cvfs.xDlClose = cFuncPointer(vfsDlCloseTrampoline)
+	cvfs.xRandomness = cFuncPointer(vfsRandomnessTrampoline)
+	cvfs.xSleep = cFuncPointer(vfsSleepTrampoline)
+	cvfs.xCurrentTime = cFuncPointer(vfsCurrentTimeTrampoline)
+	cvfs.xGetLastError = cFuncPointer(vfsGetLastErrorTrampoline)
+	cvfs.xCurrentTimeInt64 = cFuncPointer(vfsCurrentTimeInt64Trampoline)
+	return pVfs, nil
+}
+
+func lookupVFS(pVfs uintptr) VFS {
+	xvfses.mu.RLock()
+	defer xvfses.mu.RUnlock()
+	return xvfses.m[pVfs]
+}
+
+func vfsOpenTrampoline(tls *libc.TLS, pVfs uintptr, zName uintptr, pFile uintptr, flags int32, pOutFlags uintptr) int32 {
+	fw := (*vfsFileWrapper)(unsafe.Pointer(pFile))
+	fw.pMethods = 0
+	xvfses.mu.RLock()
+	vfs := xvfses.m[pVfs]
+	ioMethods := xvfses.ioMethods
+	xvfses.mu.RUnlock()
+	if vfs == nil {
+		return lib.SQLITE_CANTOPEN
+	}
+	var name string
+	if zName != 0 {
+		name = libc.GoString(zName)
+	}
+	f, outFlags, err := vfs.Open(name, OpenFlags(flags))
+	if err != nil {
+		return vfsErrCode(err, ResultCantOpen)
+	}
+	if pOutFlags != 0 {
+		*(*int32)(unsafe.Pointer(pOutFlags)) = int32(outFlags)
+	}
+
+	xfiles.mu.Lock()
+	id := xfiles.ids.next()
+	xfiles.m[id] = f
+	xfiles.mu.Unlock()
+	fw.id = id
+	fw.pMethods = ioMethods
+	return lib.SQLITE_OK
+}
+
+func vfsDeleteTrampoline(tls *libc.TLS, pVfs uintptr, zName uintptr, syncDir int32) int32 {
+	vfs := lookupVFS(pVfs)
+	if vfs == nil {
+		return lib.SQLITE_IOERR_DELETE
+	}
+	err := vfs.Delete(libc.GoString(zName), syncDir != 0)
+	if errors.Is(err, fs.ErrNotExist) {
+		return lib.SQLITE_IOERR_DELETE_NOENT
+	}
+	return vfsErrCode(err, ResultIOErrDelete)
+}
+
+func vfsAccessTrampoline(tls *libc.TLS, pVfs uintptr, zName uintptr, flags int32, pResOut uintptr) int32 {
+	vfs := lookupVFS(pVfs)
+	if vfs == nil {
+		return lib.SQLITE_IOERR_ACCESS
+	}
+	ok, err := vfs.Access(libc.GoString(zName), AccessFlag(flags))
+	if err != nil {
+		return vfsErrCode(err, ResultIOErrAccess)
+	}
+	*(*int32)(unsafe.Pointer(pResOut)) = boolToInt32(ok)
+	return lib.SQLITE_OK
+}
+
+func vfsFullPathnameTrampoline(tls *libc.TLS, pVfs uintptr, zName uintptr, nOut int32, zOut uintptr) int32 {
+	vfs := lookupVFS(pVfs)
+	if vfs == nil {
+		return lib.SQLITE_CANTOPEN
+	}
+	path, err := vfs.FullPathname(libc.GoString(zName))
+	if err != nil {
+		return vfsErrCode(err, ResultCantOpen)
+	}
+	if len(path) >= int(nOut) || strings.Contains(path, "\x00") {
+		return lib.SQLITE_CANTOPEN
+	}
+	out := unsafe.Slice((*byte)(unsafe.Pointer(zOut)), nOut)
+	copy(out, path)
+	out[len(path)] = 0
+	return lib.SQLITE_OK
+}
+
+func vfsDlOpenTrampoline(tls *libc.TLS, pVfs uintptr, zFilename uintptr) uintptr {
+	return 0
+}
+
+func vfsDlErrorTrampoline(tls *libc.TLS, pVfs uintptr, nByte int32, zErrMsg uintptr) {
+	if nByte <= 0 {
+		return
+	}
+	const msg = "loadable extensions are not supported by this VFS"
+	out := unsafe.Slice((*byte)(unsafe.Pointer(zErrMsg)), nByte)
+	n := copy(out[:len(out)-1], msg)
+	out[n] = 0
+}
+
+func vfsDlSymTrampoline(tls *libc.TLS, pVfs uintptr, pHandle uintptr, zSymbol uintptr) uintptr {
+	return 0
+}
+
+func vfsDlCloseTrampoline(tls *libc.TLS, pVfs uintptr, pHandle uintptr) {
+}
+
+func vfsRandomnessTrampoline(tls *libc.TLS, pVfs uintptr, nByte int32, zOut uintptr) int32 {
+	if nByte <= 0 {
+		return 0
+	}
+	n, _ := crand.Read(unsafe.Slice((*byte)(unsafe.Pointer(zOut)), nByte))
+	return int32(n)
+}
+
+func vfsSleepTrampoline(tls *libc.TLS, pVfs uintptr, microseconds int32) int32 {
+	time.Sleep(time.Duration(microseconds) * time.Microsecond)
+	return microseconds
+}
+
+// unixEpochJulianDayMillis is the Unix epoch
+// in milliseconds since noon in Greenwich on November 24, 4714 B.C.
+const unixEpochJulianDayMillis = 210866760000000
+
+func vfsCurrentTimeTrampoline(tls *libc.TLS, pVfs uintptr, pTimeOut uintptr) int32 {
+	ms := unixEpochJulianDayMillis + time.Now().UnixMilli()
+	*(*float64)(unsafe.Pointer(pTimeOut)) = float64(ms) / 86400000.0
+	return lib.SQLITE_OK
+}
+
+func vfsCurrentTimeInt64Trampoline(tls *libc.TLS, pVfs uintptr, pTimeOut uintptr) int32 {
+	*(*int64)(unsafe.Pointer(pTimeOut)) = unixEpochJulianDayMillis + time.Now().UnixMilli()
+	return lib.SQLITE_OK
+}
+
+func vfsGetLastErrorTrampoline(tls *libc.TLS, pVfs uintptr, nBuf int32, zBuf uintptr) int32 {
+	return 0
+}
+
+func lookupVFSFile(pFile uintptr) VFSFile {
+	id := (*vfsFileWrapper)(unsafe.Pointer(pFile)).id
+	xfiles.mu.RLock()
+	defer xfiles.mu.RUnlock()
+	return xfiles.m[id]
+}
+
+func vfsFileCloseTrampoline(tls *libc.TLS, pFile uintptr) int32 {
+	fw := (*vfsFileWrapper)(unsafe.Pointer(pFile))
+	xfiles.mu.Lock()
+	f := xfiles.m[fw.id]
+	delete(xfiles.m, fw.id)
+	xfiles.ids.reclaim(fw.id)
+	xfiles.mu.Unlock()
+	fw.id = 0
+	if f == nil {
+		return lib.SQLITE_OK
+	}
+	return vfsErrCode(f.Close(), ResultIOErrClose)
+}
+
+func vfsFileReadTrampoline(tls *libc.TLS, pFile uintptr, zBuf uintptr, iAmt int32, iOfst int64) int32 {
+	f := lookupVFSFile(pFile)
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(zBuf)), iAmt)
+	n, err := f.ReadAt(buf, iOfst)
+	if n == len(buf) {
+		return lib.SQLITE_OK
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return vfsErrCode(err, ResultIOErrRead)
+	}
+	// SQLite requires the unread portion of the buffer to be zero-filled.
+	clear(buf[n:])
+	return lib.SQLITE_IOERR_SHORT_READ
+}
+
+func vfsFileWriteTrampoline(tls *libc.TLS, pFile uintptr, zBuf uintptr, iAmt int32, iOfst int64) int32 {
+	f := lookupVFSFile(pFile)
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(zBuf)), iAmt)
+	n, err := f.WriteAt(buf, iOfst)
+	if err != nil {
+		return vfsErrCode(err, ResultIOErrWrite)
+	}
+	if n < len(buf) {
+		return lib.SQLITE_IOERR_WRITE
+	}
+	return lib.SQLITE_OK
+}
+
+func vfsFileTruncateTrampoline(tls *libc.TLS, pFile uintptr, size int64) int32 {
+	return vfsErrCode(lookupVFSFile(pFile).Truncate(size), ResultIOErrTruncate)
+}
+
+func vfsFileSyncTrampoline(tls *libc.TLS, pFile uintptr, flags int32) int32 {
+	return vfsErrCode(lookupVFSFile(pFile).Sync(SyncFlag(flags)), ResultIOErrFsync)
+}
+
+func vfsFileSizeTrampoline(tls *libc.TLS, pFile uintptr, pSize uintptr) int32 {
+	size, err := lookupVFSFile(pFile).FileSize()
+	if err != nil {
+		return vfsErrCode(err, ResultIOErrFstat)
+	}
+	*(*int64)(unsafe.Pointer(pSize)) = size
+	return lib.SQLITE_OK
+}
+
+func vfsFileLockTrampoline(tls *libc.TLS, pFile uintptr, level int32) int32 {
+	return vfsErrCode(lookupVFSFile(pFile).Lock(LockLevel(level)), ResultIOErrLock)
+}
+
+func vfsFileUnlockTrampoline(tls *libc.TLS, pFile uintptr, level int32) int32 {
+	return vfsErrCode(lookupVFSFile(pFile).Unlock(LockLevel(level)), ResultIOErrUnlock)
+}
+
+func vfsFileCheckReservedLockTrampoline(tls *libc.TLS, pFile uintptr, pResOut uintptr) int32 {
+	reserved, err := lookupVFSFile(pFile).CheckReservedLock()
+	if err != nil {
+		return vfsErrCode(err, ResultIOErrCheckReservedLock)
+	}
+	*(*int32)(unsafe.Pointer(pResOut)) = boolToInt32(reserved)
+	return lib.SQLITE_OK
+}
+
+func vfsFileControlTrampoline(tls *libc.TLS, pFile uintptr, op int32, pArg uintptr) int32 {
+	return lib.SQLITE_NOTFOUND
+}
+
+func vfsFileSectorSizeTrampoline(tls *libc.TLS, pFile uintptr) int32 {
+	return int32(lookupVFSFile(pFile).SectorSize())
+}
+
+func vfsFileDeviceCharacteristicsTrampoline(tls *libc.TLS, pFile uintptr) int32 {
+	return int32(lookupVFSFile(pFile).DeviceCharacteristics())
+}
+
+// vfsErrCode returns the result code for an error returned by a VFS method.
+// Errors that do not carry a result code are reported as dflt.
+func vfsErrCode(err error, dflt ResultCode) int32 {
+	if err == nil {
+		return lib.SQLITE_OK
+	}
+	if e := new(sqliteError); errors.As(err, e) {
+		return int32(e.code)
+	}
+	return int32(dflt)
+}
+
+func boolToInt32(b bool) int32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// cVFS mirrors the C sqlite3_vfs struct.
+// modernc.org/sqlite/lib does not export a portable name for it.
+type cVFS struct {
+	iVersion          int32
+	szOsFile          int32
+	mxPathname        int32
+	pNext             uintptr
+	zName             uintptr
+	pAppData          uintptr
+	xOpen             uintptr
+	xDelete           uintptr
+	xAccess           uintptr
+	xFullPathname     uintptr
+	xDlOpen           uintptr
+	xDlError          uintptr
+	xDlSym            uintptr
+	xDlClose          uintptr
+	xRandomness       uintptr
+	xSleep            uintptr
+	xCurrentTime      uintptr
+	xGetLastError     uintptr
+	xCurrentTimeInt64 uintptr
+	xSetSystemCall    uintptr
+	xGetSystemCall    uintptr
+	xNextSystemCall   uintptr
+}
+
+// cIOMethods mirrors the C sqlite3_io_methods struct.
+type cIOMethods struct {
+	iVersion               int32
+	xClose                 uintptr
+	xRead                  uintptr
+	xWrite                 uintptr
+	xTruncate              uintptr
+	xSync                  uintptr
+	xFileSize              uintptr
+	xLock                  uintptr
+	xUnlock                uintptr
+	xCheckReservedLock     uintptr
+	xFileControl           uintptr
+	xSectorSize            uintptr
+	xDeviceCharacteristics uintptr
+	xShmMap                uintptr
+	xShmLock               uintptr
+	xShmBarrier            uintptr
+	xShmUnmap              uintptr
+	xFetch                 uintptr
+	xUnfetch               uintptr
+}
+
+// vfsFileWrapper is the sqlite3_file subclass allocated by SQLite for each open file.
+type vfsFileWrapper struct {
+	pMethods uintptr // sqlite3_file base
+	id       uintptr
+}
+
+var (
+	xvfses = struct {
+		mu        sync.RWMutex
+		m         map[uintptr]VFS    // sqlite3_vfs* -> VFS
+		names     map[string]uintptr // name -> sqlite3_vfs*
+		ioMethods uintptr            // sqlite3_io_methods* shared by all files
+	}{
+		m:     make(map[uintptr]VFS),
+		names: make(map[string]uintptr),
+	}
+	xfiles = struct {
+		mu  sync.RWMutex
+		m   map[uintptr]VFSFile
+		ids idGen
+	}{
+		m: make(map[uintptr]VFSFile),
+	}
+)
@@ -0,0 +1,222 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestRegisterVFS(t *testing.T) {
+	vfs := newMemVFS()
+	if err := sqlite.RegisterVFS("memtest", vfs, false); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := sqlite.UnregisterVFS("memtest"); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	flags := sqlite.OpenReadWrite | sqlite.OpenCreate | sqlite.OpenURI
+	c, err := sqlite.OpenConn("file:/test.db?vfs=memtest", flags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	err = sqlitex.ExecuteScript(c, `
+		CREATE TABLE foo (x INTEGER);
+		INSERT INTO foo VALUES (1), (2), (3);
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum, err := sqlitex.ResultInt(c.Prep("SELECT sum(x) FROM foo;"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 6 {
+		t.Errorf("sum(x) = %d; want 6", sum)
+	}
+	if size := vfs.size("/test.db"); size == 0 {
+		t.Error("database file not written to VFS")
+	}
+
+	t.Run("WriteFault", func(t *testing.T) {
+		vfs.setWriteErr(errors.New("disk on fire"))
+		defer vfs.setWriteErr(nil)
+		err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (4);", nil)
+		if err == nil {
+			t.Fatal("INSERT did not return an error")
+		}
+		if got := sqlite.ErrCode(err).ToPrimary(); got != sqlite.ResultIOErr {
+			t.Errorf("INSERT error code = %v; want %v", sqlite.ErrCode(err), sqlite.ResultIOErr)
+		}
+	})
+
+	t.Run("BusyFault", func(t *testing.T) {
+		vfs.setWriteErr(sqlite.ResultBusy.ToError())
+		defer vfs.setWriteErr(nil)
+		err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (4);", nil)
+		if got, want := sqlite.ErrCode(err), sqlite.ResultBusy; got != want {
+			t.Errorf("INSERT error code = %v; want %v", got, want)
+		}
+	})
+}
+
+// memVFS is an in-memory VFS that can inject write errors.
+type memVFS struct {
+	mu       sync.Mutex
+	files    map[string]*memFileData
+	nextTemp int
+	writeErr error
+}
+
+type memFileData struct {
+	data []byte
+}
+
+func newMemVFS() *memVFS {
+	return &memVFS{files: make(map[string]*memFileData)}
+}
+
+func (vfs *memVFS) setWriteErr(err error) {
+	vfs.mu.Lock()
+	defer vfs.mu.Unlock()
+	vfs.writeErr = err
+}
+
+func (vfs *memVFS) size(name string) int {
+	vfs.mu.Lock()
+	defer vfs.mu.Unlock()
+	if fd := vfs.files[name]; fd != nil {
+		return len(fd.data)
+	}
+	return 0
+}
+
+func (vfs *memVFS) Open(name string, flags sqlite.OpenFlags) (sqlite.VFSFile, sqlite.OpenFlags, error) {
+	vfs.mu.Lock()
+	defer vfs.mu.Unlock()
+	if name == "" {
+		vfs.nextTemp++
+		name = fmt.Sprintf("/temp%d", vfs.nextTemp)
+		flags |= sqlite.OpenDeleteOnClose
+	}
+	fd := vfs.files[name]
+	if fd == nil {
+		if flags&sqlite.OpenCreate == 0 {
+			return nil, 0, fs.ErrNotExist
+		}
+		fd = new(memFileData)
+		vfs.files[name] = fd
+	}
+	return &memFile{vfs: vfs, name: name, fd: fd, deleteOnClose: flags&sqlite.OpenDeleteOnClose != 0}, flags, nil
+}
+
+func (vfs *memVFS) Delete(name string, syncDir bool) error {
+	vfs.mu.Lock()
+	defer vfs.mu.Unlock()
+	if vfs.files[name] == nil {
+		return fs.ErrNotExist
+	}
+	delete(vfs.files, name)
+	return nil
+}
+
+func (vfs *memVFS) Access(name string, flag sqlite.AccessFlag) (bool, error) {
+	vfs.mu.Lock()
+	defer vfs.mu.Unlock()
+	return vfs.files[name] != nil, nil
+}
+
+func (vfs *memVFS) FullPathname(name string) (string, error) {
+	return name, nil
+}
+
+type memFile struct {
+	vfs           *memVFS
+	name          string
+	fd            *memFileData
+	deleteOnClose bool
+}
+
+func (f *memFile) ReadAt(p []byte, off int64) (int, error) {
+	f.vfs.mu.Lock()
+	defer f.vfs.mu.Unlock()
+	if off >= int64(len(f.fd.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.fd.data[off:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) WriteAt(p []byte, off int64) (int, error) {
+	f.vfs.mu.Lock()
+	defer f.vfs.mu.Unlock()
+	if f.vfs.writeErr != nil {
+		return 0, f.vfs.writeErr
+	}
+	if end := off + int64(len(p)); end > int64(len(f.fd.data)) {
+		f.fd.data = append(f.fd.data, make([]byte, end-int64(len(f.fd.data)))...)
+	}
+	return copy(f.fd.data[off:], p), nil
+}
+
+func (f *memFile) Truncate(size int64) error {
+	f.vfs.mu.Lock()
+	defer f.vfs.mu.Unlock()
+	if size < int64(len(f.fd.data)) {
+		f.fd.data = f.fd.data[:size]
+	}
+	return nil
+}
+
+func (f *memFile) Sync(flags sqlite.SyncFlag) error {
+	return nil
+}
+
+func (f *memFile) FileSize() (int64, error) {
+	f.vfs.mu.Lock()
+	defer f.vfs.mu.Unlock()
+	return int64(len(f.fd.data)), nil
+}
+
+func (f *memFile) Lock(level sqlite.LockLevel) error   { return nil }
+func (f *memFile) Unlock(level sqlite.LockLevel) error { return nil }
+
+func (f *memFile) CheckReservedLock() (bool, error) {
+	return false, nil
+}
+
+func (f *memFile) SectorSize() int {
+	return 512
+}
+
+func (f *memFile) DeviceCharacteristics() sqlite.DeviceCharacteristics {
+	return 0
+}
+
+func (f *memFile) Close() error {
+	if f.deleteOnClose {
+		f.vfs.mu.Lock()
+		delete(f.vfs.files, f.name)
+		f.vfs.mu.Unlock()
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite
+
+import (
+	"fmt"
+	"unsafe"
+
+	"modernc.org/libc"
+	"modernc.org/libc/sys/types"
+	lib "modernc.org/sqlite/lib"
+)
+
+// CheckpointMode is a [checkpoint mode] passed to [Conn.Checkpoint].
+//
+// [checkpoint mode]: https://sqlite.org/c3ref/c_checkpoint_full.html
+type CheckpointMode int32
+
+// Checkpoint modes.
+const (
+	// CheckpointPassive checkpoints as many frames as possible
+	// without waiting for any database readers or writers to finish.
+	CheckpointPassive CheckpointMode = lib.SQLITE_CHECKPOINT_PASSIVE
+	// CheckpointFull blocks (using the connection's busy handler)
+	// until there is no database writer and all readers are reading
+	// from the most recent database snapshot,
+	// then checkpoints all frames in the log file.
+	CheckpointFull CheckpointMode = lib.SQLITE_CHECKPOINT_FULL
+	// CheckpointRestart works like CheckpointFull
+	// and then also waits until all readers have finished with the log file.
+	// This ensures that the next writer will restart the log file from the beginning.
+	CheckpointRestart CheckpointMode = lib.SQLITE_CHECKPOINT_RESTART
+	// CheckpointTruncate works like CheckpointRestart
+	// and then also truncates the log file to zero bytes.
+	CheckpointTruncate CheckpointMode = lib.SQLITE_CHECKPOINT_TRUNCATE
+)
+
+// String returns the C constant name of the checkpoint mode.
+func (mode CheckpointMode) String() string {
+	switch mode {
+	case CheckpointPassive:
+		return "SQLITE_CHECKPOINT_PASSIVE"
+	case CheckpointFull:
+		return "SQLITE_CHECKPOINT_FULL"
+	case CheckpointRestart:
+		return "SQLITE_CHECKPOINT_RESTART"
+	case CheckpointTruncate:
+		return "SQLITE_CHECKPOINT_TRUNCATE"
+	default:
+		return fmt.Sprintf("CheckpointMode(%d)", int32(mode))
+	}
+}
+
+// Checkpoint runs a checkpoint operation on the [write-ahead log]
+// of the database with the given name (e.g. "main" or the name of an attached database).
+// An empty database name is treated as "main".
+// logFrames is the total number of frames in the log file
+// and checkpointed is the number of frames in the log file
+// that were checkpointed.
+// Both are -1 if the database is not in WAL mode.
+//
+// If another connection is holding a lock that prevents the checkpoint from completing,
+// Checkpoint returns a [ResultBusy] error along with the frame counts.
+//
+// https://sqlite.org/c3ref/wal_checkpoint_v2.html
+//
+// [write-ahead log]: https://sqlite.org/wal.html
+func (c *Conn) Checkpoint(db string, mode CheckpointMode) (logFrames, checkpointed int, err error) {
+	if c == nil {
+		return -1, -1, fmt.Errorf("sqlite: checkpoint %q: nil connection", db)
+	}
+	cdb, freeCDB, err := cDBName(db)
+	if err != nil {
+		return -1, -1, fmt.Errorf("sqlite: checkpoint %q: %v", db, err)
+	}
+	defer freeCDB()
+	counts, err := malloc(c.tls, 2*types.Size_t(unsafe.Sizeof(int32(0))))
+	if err != nil {
+		return -1, -1, fmt.Errorf("sqlite: checkpoint %q: %v", db, err)
+	}
+	defer libc.Xfree(c.tls, counts)
+	pnLog := counts
+	pnCkpt := counts + unsafe.Sizeof(int32(0))
+	*(*int32)(unsafe.Pointer(pnLog)) = -1
+	*(*int32)(unsafe.Pointer(pnCkpt)) = -1
+
+	res := ResultCode(lib.Xsqlite3_wal_checkpoint_v2(c.tls, c.conn, cdb, int32(mode), pnLog, pnCkpt))
+	logFrames = int(*(*int32)(unsafe.Pointer(pnLog)))
+	checkpointed = int(*(*int32)(unsafe.Pointer(pnCkpt)))
+	if err := c.extreserr(res); err != nil {
+		return logFrames, checkpointed, fmt.Errorf("sqlite: checkpoint %q: %w", db, err)
+	}
+	return logFrames, checkpointed, nil
+}
+
+// SetAutoCheckpoint causes the connection to automatically checkpoint
+// after committing a transaction
+// if there are n or more frames in the [write-ahead log] file.
+// Passing a non-positive n turns off automatic checkpoints.
+// New connections automatically checkpoint after 1000 frames.
+//
+// Automatic checkpoints are implemented with a WAL hook,
+// so calling SetAutoCheckpoint replaces any function registered with [Conn.SetWALHook]
+// and vice versa.
+//
+// https://sqlite.org/c3ref/wal_autocheckpoint.html
+//
+// [write-ahead log]: https://sqlite.org/wal.html
+func (c *Conn) SetAutoCheckpoint(n int) error {
+	if c == nil {
+		return fmt.Errorf("sqlite: set auto checkpoint: nil connection")
+	}
+	if n < 0 {
+		n = 0
+	}
+	res := ResultCode(lib.Xsqlite3_wal_autocheckpoint(c.tls, c.conn, int32(n)))
+	hooks.mu.Lock()
+	delete(hooks.wal, c.conn)
+	hooks.mu.Unlock()
+	if err := res.ToError(); err != nil {
+		return fmt.Errorf("sqlite: set auto checkpoint: %w", err)
+	}
+	return nil
+}
+
+// SetWALHook registers a function that is called
+// each time a transaction is committed to a database in WAL mode.
+// dbName is the name of the database that was written to (e.g. "main")
+// and frames is the number of frames currently in the log file.
+// The hook is called after the write lock has been released,
+// so the hook may read, write, or checkpoint the database (e.g. with [Conn.Checkpoint]).
+// An error returned from the hook is reported by the statement
+// that committed the transaction,
+// but the transaction remains committed.
+//
+// SetWALHook replaces the automatic checkpoint behavior
+// configured by [Conn.SetAutoCheckpoint].
+// SetWALHook(nil) clears any WAL hook previously set
+// without restoring automatic checkpoints.
+//
+// https://sqlite.org/c3ref/wal_hook.html
+func (c *Conn) SetWALHook(fn func(dbName string, frames int) error) {
+	if c == nil {
+		return
+	}
+	if fn == nil {
+		lib.Xsqlite3_wal_hook(c.tls, c.conn, 0, 0)
+		hooks.mu.Lock()
+		delete(hooks.wal, c.conn)
+		hooks.mu.Unlock()
+		return
+	}
+	hooks.mu.Lock()
+	if hooks.wal == nil {
+		hooks.wal = make(map[uintptr]func(string, int) error)
+	}
+	hooks.wal[c.conn] = fn
+	hooks.mu.Unlock()
+	lib.Xsqlite3_wal_hook(c.tls, c.conn, cFuncPointer(walHookTrampoline), c.conn)
+}
+
+func walHookTrampoline(tls *libc.TLS, conn uintptr, db uintptr, cDB uintptr, frames int32) int32 {
+	hooks.mu.RLock()
+	fn := hooks.wal[conn]
+	hooks.mu.RUnlock()
+	if fn == nil {
+		return lib.SQLITE_OK
+	}
+	if err := fn(libc.GoString(cDB), int(frames)); err != nil {
+		return int32(ErrCode(err))
+	}
+	return lib.SQLITE_OK
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 Roxy Light
+// SPDX-License-Identifier: ISC
+
+package sqlite_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"zombiezen.com/go/sqlite"
+	"zombiezen.com/go/sqlite/sqlitex"
+)
+
+func TestCheckpoint(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "wal.db")
+	c, err := sqlite.OpenConn(dbPath, sqlite.OpenReadWrite|sqlite.OpenCreate|sqlite.OpenWAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := c.SetAutoCheckpoint(0); err != nil {
+		t.Fatal(err)
+	}
+	err = sqlitex.ExecuteScript(c, `
+		CREATE TABLE foo (x INTEGER);
+		INSERT INTO foo VALUES (1), (2), (3);
+	`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logFrames, checkpointed, err := c.Checkpoint("", sqlite.CheckpointTruncate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logFrames != checkpointed {
+		t.Errorf("Checkpoint(...) = %d, %d, <nil>; want equal frame counts", logFrames, checkpointed)
+	}
+	info, err := os.Stat(dbPath + "-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("WAL file size after %v = %d; want 0", sqlite.CheckpointTruncate, info.Size())
+	}
+
+	if _, _, err := c.Checkpoint("bogus", sqlite.CheckpointPassive); err == nil {
+		t.Error("Checkpoint on unknown database did not return an error")
+	}
+}
+
+func TestSetWALHook(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "wal.db")
+	c, err := sqlite.OpenConn(dbPath, sqlite.OpenReadWrite|sqlite.OpenCreate|sqlite.OpenWAL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := c.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if err := sqlitex.ExecuteTransient(c, "CREATE TABLE foo (x INTEGER);", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := 0
+	c.SetWALHook(func(dbName string, frames int) error {
+		calls++
+		if dbName != "main" {
+			t.Errorf("dbName = %q; want \"main\"", dbName)
+		}
+		if frames <= 0 {
+			t.Errorf("frames = %d; want >0", frames)
+		}
+		_, _, err := c.Checkpoint(dbName, sqlite.CheckpointTruncate)
+		return err
+	})
+	if err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (1);", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Errorf("WAL hook called %d times; want 1", calls)
+	}
+	info, err := os.Stat(dbPath + "-wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("WAL file size after hook checkpoint = %d; want 0", info.Size())
+	}
+
+	calls = 0
+	c.SetWALHook(nil)
+	if err := sqlitex.ExecuteTransient(c, "INSERT INTO foo VALUES (2);", nil); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("WAL hook called %d times after being cleared", calls)
+	}
+}